@@ -0,0 +1,80 @@
+package querystring
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// CanonicalProfile describes the canonical query string expected by a request signing scheme.
+// A canonical query string percent-encodes names and values as defined by RFC 3986
+// ("%20" for spaces, unreserved characters such as '~' left alone) and sorts the
+// parameters byte-wise by encoded name, then by encoded value.
+type CanonicalProfile struct {
+	// Exclude lists the parameters left out of the canonical form,
+	// typically the parameter carrying the signature itself.
+	Exclude []string
+}
+
+var (
+	// SigV4 is the canonical query string of AWS Signature Version 4.
+	SigV4 = CanonicalProfile{
+		Exclude: []string{"X-Amz-Signature"},
+	}
+	// OAuth1 is the normalized request parameters of OAuth 1.0a (RFC 5849, section 3.4.1.3.2).
+	OAuth1 = CanonicalProfile{
+		Exclude: []string{"oauth_signature"},
+	}
+)
+
+// Canonicalize returns the canonical query string of values for the profile.
+func Canonicalize(values url.Values, profile CanonicalProfile) string {
+	type pair struct {
+		name, value string
+	}
+	var pairs []pair
+	for name, vs := range values {
+		if containsString(profile.Exclude, name) {
+			continue
+		}
+		encoded := percentEncode(name, false)
+		for _, v := range vs {
+			pairs = append(pairs, pair{name: encoded, value: percentEncode(v, false)})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].name != pairs[j].name {
+			return pairs[i].name < pairs[j].name
+		}
+		return pairs[i].value < pairs[j].value
+	})
+
+	var builder strings.Builder
+	for i, p := range pairs {
+		if i > 0 {
+			builder.WriteByte('&')
+		}
+		builder.WriteString(p.name)
+		builder.WriteByte('=')
+		builder.WriteString(p.value)
+	}
+	return builder.String()
+}
+
+// Canonical converts v to url.Values and returns their canonical query string for the profile.
+func (c *Converter) Canonical(v interface{}, profile CanonicalProfile) (string, error) {
+	values, err := c.Values(v)
+	if err != nil {
+		return "", err
+	}
+	return Canonicalize(values, profile), nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package querystring
+
+import (
+	"net/url"
+	"testing"
+)
+
+// TestCanonicalizeOAuth1 uses the example of RFC 5849, section 3.4.1.3.
+func TestCanonicalizeOAuth1(t *testing.T) {
+	values := url.Values{
+		"b5":                     {"=%3D"},
+		"a3":                     {"a", "2 q"},
+		"c@":                     {""},
+		"a2":                     {"r b"},
+		"oauth_consumer_key":     {"9djdj82h48djs9d2"},
+		"oauth_token":            {"kkk9d7dh3k39sjv7"},
+		"oauth_signature_method": {"HMAC-SHA1"},
+		"oauth_timestamp":        {"137131201"},
+		"oauth_nonce":            {"7d8f3e4a"},
+		"oauth_signature":        {"djosJKDKJSD8743243%2Fjdk33klY%3D"},
+		"c2":                     {""},
+	}
+	expected := "a2=r%20b&a3=2%20q&a3=a&b5=%3D%253D&c%40=&c2=&oauth_consumer_key=9djdj82h48djs9d2" +
+		"&oauth_nonce=7d8f3e4a&oauth_signature_method=HMAC-SHA1&oauth_timestamp=137131201" +
+		"&oauth_token=kkk9d7dh3k39sjv7"
+	if got := Canonicalize(values, OAuth1); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+// TestCanonicalizeSigV4 uses the query cases of the AWS Signature Version 4 test suite.
+func TestCanonicalizeSigV4(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   url.Values
+		expected string
+	}{
+		{"get-vanilla-query-order-key-case", url.Values{"Param2": {"value2"}, "Param1": {"value1"}}, "Param1=value1&Param2=value2"},
+		{"get-vanilla-query-order-value", url.Values{"Param1": {"value2", "value1"}}, "Param1=value1&Param1=value2"},
+		{"get-vanilla-query-unreserved", url.Values{
+			"-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz": {"-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"},
+		}, "-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz=-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"},
+		{"get-vanilla-utf8-query", url.Values{"ሴ": {"bar"}}, "%E1%88%B4=bar"},
+		{"signature excluded", url.Values{"X-Amz-Signature": {"abc"}, "Action": {"ListUsers"}}, "Action=ListUsers"},
+	}
+	for _, tt := range tests {
+		if got := Canonicalize(tt.values, SigV4); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestConverterCanonical(t *testing.T) {
+	type Input struct {
+		Action  string `url:"Action"`
+		Version string `url:"Version"`
+		Query   string `url:"q"`
+	}
+	got, err := NewConverter(NewTag()).Canonical(Input{Action: "ListUsers", Version: "2010-05-08", Query: "a b+c"}, SigV4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Action=ListUsers&Version=2010-05-08&q=a%20b%2Bc" {
+		t.Errorf("unexpected canonical query %q", got)
+	}
+}
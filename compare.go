@@ -0,0 +1,120 @@
+package querystring
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Change is a key whose values differ between two url.Values.
+type Change struct {
+	Key string
+	Old []string
+	New []string
+}
+
+// Comparison is the semantic difference between two url.Values, as returned by CompareValues.
+// The order of the keys is not significant.
+type Comparison struct {
+	// Added holds the keys of the values after that the values before do not have.
+	Added url.Values
+	// Removed holds the keys of the values before that the values after do not have.
+	Removed url.Values
+	// Changed holds the keys whose values differ, sorted by key.
+	Changed []Change
+}
+
+// CompareValues compares the url.Values before and after.
+// Values of a key are compared in order, unless WithIgnoreValueOrder is set.
+func CompareValues(before, after url.Values, opts ...CompareOption) *Comparison {
+	opt := defaultCompareOption()
+	for _, o := range opts {
+		o(opt)
+	}
+	cmp := &Comparison{Added: make(url.Values), Removed: make(url.Values)}
+	for k, vs := range before {
+		nvs, ok := after[k]
+		if !ok {
+			cmp.Removed[k] = vs
+			continue
+		}
+		if !equalValues(vs, nvs, opt.ignoreOrder) {
+			cmp.Changed = append(cmp.Changed, Change{Key: k, Old: vs, New: nvs})
+		}
+	}
+	for k, vs := range after {
+		if _, ok := before[k]; !ok {
+			cmp.Added[k] = vs
+		}
+	}
+	sort.Slice(cmp.Changed, func(i, j int) bool {
+		return cmp.Changed[i].Key < cmp.Changed[j].Key
+	})
+	return cmp
+}
+
+// Equal reports whether the compared values are the same.
+func (c *Comparison) Equal() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// String renders the comparison one key per line, sorted by key:
+// "+ key=value" for added values, "- key=value" for removed values and
+// "~ key: [old] -> [new]" for changed keys. It returns "" if the values are equal.
+func (c *Comparison) String() string {
+	type line struct {
+		key  string
+		text string
+	}
+	var lines []line
+	for _, k := range sortedKeys(c.Added) {
+		for _, v := range c.Added[k] {
+			lines = append(lines, line{k, fmt.Sprintf("+ %s=%s", k, v)})
+		}
+	}
+	for _, k := range sortedKeys(c.Removed) {
+		for _, v := range c.Removed[k] {
+			lines = append(lines, line{k, fmt.Sprintf("- %s=%s", k, v)})
+		}
+	}
+	for _, ch := range c.Changed {
+		lines = append(lines, line{ch.Key, fmt.Sprintf("~ %s: %q -> %q", ch.Key, ch.Old, ch.New)})
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].key < lines[j].key
+	})
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.text
+	}
+	return strings.Join(texts, "\n")
+}
+
+// equalValues reports whether a and b hold the same values, in the same order unless ignoreOrder is set.
+func equalValues(a, b []string, ignoreOrder bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	if ignoreOrder {
+		a = append([]string(nil), a...)
+		b = append([]string(nil), b...)
+		sort.Strings(a)
+		sort.Strings(b)
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,44 @@
+package querystring
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCompareValues(t *testing.T) {
+	before := url.Values{"a": {"1"}, "b": {"2"}, "tags": {"x", "y"}, "q": {"go"}}
+	after := url.Values{"q": {"go"}, "a": {"3"}, "c": {"4", "5"}, "tags": {"y", "x"}}
+
+	cmp := CompareValues(before, after)
+	if cmp.Equal() {
+		t.Fatal("expected differences")
+	}
+	if !reflect.DeepEqual(cmp.Added, url.Values{"c": {"4", "5"}}) {
+		t.Errorf("unexpected added values %v", cmp.Added)
+	}
+	if !reflect.DeepEqual(cmp.Removed, url.Values{"b": {"2"}}) {
+		t.Errorf("unexpected removed values %v", cmp.Removed)
+	}
+	expect := []Change{
+		{Key: "a", Old: []string{"1"}, New: []string{"3"}},
+		{Key: "tags", Old: []string{"x", "y"}, New: []string{"y", "x"}},
+	}
+	if !reflect.DeepEqual(cmp.Changed, expect) {
+		t.Errorf("unexpected changed values %v", cmp.Changed)
+	}
+	rendered := "~ a: [\"1\"] -> [\"3\"]\n- b=2\n+ c=4\n+ c=5\n~ tags: [\"x\" \"y\"] -> [\"y\" \"x\"]"
+	if actual := cmp.String(); actual != rendered {
+		t.Errorf("expected\n%s\ngot\n%s", rendered, actual)
+	}
+
+	cmp = CompareValues(before, after, WithIgnoreValueOrder(true))
+	if len(cmp.Changed) != 1 || cmp.Changed[0].Key != "a" {
+		t.Errorf("expected only key a to change, got %v", cmp.Changed)
+	}
+
+	cmp = CompareValues(url.Values{"a": {"1", "2"}}, url.Values{"a": {"2", "1"}}, WithIgnoreValueOrder(true))
+	if !cmp.Equal() || cmp.String() != "" {
+		t.Errorf("expected equal values, got %q", cmp.String())
+	}
+}
@@ -0,0 +1,90 @@
+package querystring
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testCursor struct {
+	LastID    int64     `url:"id"`
+	CreatedAt time.Time `url:"t"`
+}
+
+func TestCursorCodec(t *testing.T) {
+	in := testCursor{LastID: 1234, CreatedAt: time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)}
+	for _, opts := range [][]CursorOption{nil, {WithCursorKey([]byte("secret"))}} {
+		codec := NewCursorCodec(NewConverter(NewTag()), 1, opts...)
+		cursor, err := codec.Encode(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out testCursor
+		if err := codec.Decode(cursor, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !out.CreatedAt.Equal(in.CreatedAt) || out.LastID != in.LastID {
+			t.Errorf("expected %+v, got %+v", in, out)
+		}
+
+		next := NewCursorCodec(NewConverter(NewTag()), 2, opts...)
+		var versionErr *CursorVersionError
+		if err := next.Decode(cursor, &out); !errors.As(err, &versionErr) || versionErr.Version != 1 || versionErr.Expected != 2 {
+			t.Errorf("expected a CursorVersionError, got %v", err)
+		}
+	}
+}
+
+func TestCursorCodecForged(t *testing.T) {
+	signed := NewCursorCodec(NewConverter(NewTag()), 1, WithCursorKey([]byte("secret")))
+	unsigned := NewCursorCodec(NewConverter(NewTag()), 1)
+
+	forged, err := unsigned.Encode(testCursor{LastID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out testCursor
+	if err := signed.Decode(forged, &out); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+	if err := signed.Decode("not a cursor", &out); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestCursorCodecBadValues(t *testing.T) {
+	codec := NewCursorCodec(NewConverter(NewTag()), 1)
+	cursor := base64.RawURLEncoding.EncodeToString(append([]byte{1}, "id=abc"...))
+	var out testCursor
+	if err := codec.Decode(cursor, &out); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+	if err := codec.Decode(cursor, out); errors.Is(err, ErrInvalidCursor) || err == nil {
+		t.Errorf("expected a destination error, got %v", err)
+	}
+}
+
+func TestCursorCodecNested(t *testing.T) {
+	type Position struct {
+		ID   int64  `url:"id"`
+		Sort string `url:"sort"`
+	}
+	type Cursor struct {
+		After Position `url:"after"`
+		Desc  bool     `url:"desc"`
+	}
+	codec := NewCursorCodec(NewConverter(NewTag()), 1)
+	in := Cursor{After: Position{ID: 9, Sort: "name"}, Desc: true}
+	cursor, err := codec.Encode(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out Cursor
+	if err := codec.Decode(cursor, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+}
@@ -0,0 +1,460 @@
+package querystring
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	decoderType = reflect.TypeOf((*Decoder)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// Decoder is an interface implemented by any type that wishes to decode
+// itself from URL values in a non-standard way.
+// It is the counterpart of Encoder and receives every value of its key.
+type Decoder interface {
+	Decode(values []string) error
+}
+
+// Decode stores url.Values into the struct pointed to by dst.
+// It reverses Values: fields are named by the same tag and are decoded
+// according to the style of the Converter. Keys without a matching field are
+// stored in the remain field, if any, or else handled by the unknown keys policy
+// set with WithUnknownKeys, which ignores them by default.
+// The OpenAPI style and explode tag options are not taken into account.
+// The call options Only and Except select the fields to decode; the keys of
+// the other fields are handled as unknown keys.
+func (c *Converter) Decode(values url.Values, dst interface{}, opts ...CallOption) error {
+	vf := reflect.ValueOf(dst)
+	if vf.Kind() != reflect.Ptr || vf.IsNil() {
+		return fmt.Errorf("decode destination must be a non-nil pointer, got %T", dst)
+	}
+	vf = vf.Elem()
+	if vf.Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported type %T", dst)
+	}
+	p, err := c.plan(vf.Type())
+	if err != nil {
+		return err
+	}
+	fields, err := selectFields(p, opts)
+	if err != nil {
+		return err
+	}
+	return c.decodeFields(values, vf, "", "", nil, fields)
+}
+
+// Presence is the set of struct fields that were present in decoded values.
+// It is keyed by Go field path, such as "Filter.Name" for the field Name
+// of the nested struct field Filter.
+type Presence map[string]bool
+
+// Has reports whether the field at path was present.
+func (p Presence) Has(path string) bool {
+	return p[path]
+}
+
+// DecodePresence stores url.Values into the struct pointed to by dst like Decode,
+// and returns the set of fields whose keys were present in values.
+func (c *Converter) DecodePresence(values url.Values, dst interface{}, opts ...CallOption) (Presence, error) {
+	vf := reflect.ValueOf(dst)
+	if vf.Kind() != reflect.Ptr || vf.IsNil() {
+		return nil, fmt.Errorf("decode destination must be a non-nil pointer, got %T", dst)
+	}
+	vf = vf.Elem()
+	if vf.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported type %T", dst)
+	}
+	p, err := c.plan(vf.Type())
+	if err != nil {
+		return nil, err
+	}
+	fields, err := selectFields(p, opts)
+	if err != nil {
+		return nil, err
+	}
+	presence := make(Presence)
+	if err := c.decodeFields(values, vf, "", "", presence, fields); err != nil {
+		return nil, err
+	}
+	return presence, nil
+}
+
+// decodeStruct decodes the fields of val nested in scope.
+// path is the Go field path of val; when presence is not nil, the paths of
+// the fields found in values are added to it.
+// When the struct is the top-level value, the keys that are not claimed by
+// any field are stored in its remain field, if any, or handled according to
+// the unknown keys policy.
+func (c *Converter) decodeStruct(values url.Values, val reflect.Value, scope, path string, presence Presence) error {
+	p, err := c.plan(val.Type())
+	if err != nil {
+		return err
+	}
+	return c.decodeFields(values, val, scope, path, presence, p.fields)
+}
+
+// decodeFields decodes the fields of the struct val like decodeStruct.
+func (c *Converter) decodeFields(values url.Values, val reflect.Value, scope, path string, presence Presence, fields []field) error {
+	var remain *field
+	var claimed []field
+	for i := range fields {
+		f := &fields[i]
+		if f.opts.Contains("path") {
+			continue
+		}
+		if f.opts.Contains("remain") {
+			if err := checkRemain(*f); err != nil {
+				return err
+			}
+			remain = f
+			continue
+		}
+		key := c.style.nestKey(scope, f.name)
+		claimed = append(claimed, *f)
+		if !c.hasValues(values, key, f.typ) {
+			continue
+		}
+		if f.until != "" && c.deprecated != nil {
+			c.deprecated(key, f.until)
+		}
+		fieldPath := f.path
+		if path != "" {
+			fieldPath = path + "." + f.path
+		}
+		if presence != nil {
+			presence[fieldPath] = true
+		}
+		fv, ok := fieldValue(val, f.index, true)
+		if !ok {
+			continue
+		}
+		if err := c.decodeField(values, key, fieldPath, fv, presence); err != nil {
+			return fmt.Errorf("field %s: %w", f.path, err)
+		}
+	}
+	if scope != "" {
+		return nil
+	}
+	if remain == nil {
+		return c.checkUnknown(values, claimed)
+	}
+	unclaimed := c.unclaimed(values, claimed)
+	if len(unclaimed) > 0 {
+		fv, ok := fieldValue(val, remain.index, true)
+		if ok {
+			fv.Set(reflect.ValueOf(map[string][]string(unclaimed)).Convert(fv.Type()))
+		}
+		if presence != nil {
+			presence[remain.path] = true
+		}
+	}
+	return nil
+}
+
+// decodeField decodes the struct field fv, recursing into nested structs
+// so that their fields are recorded in presence.
+func (c *Converter) decodeField(values url.Values, name, path string, fv reflect.Value, presence Presence) error {
+	if presence == nil || c.style.Nest == NestNone {
+		return c.decodeValue(values, name, fv)
+	}
+	typ := fv.Type()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || typ == timeType ||
+		reflect.PointerTo(typ).Implements(decoderType) || reflect.PointerTo(typ).Implements(scannerType) {
+		return c.decodeValue(values, name, fv)
+	}
+	if _, ok := optionalElem(typ); ok {
+		return c.decodeValue(values, name, fv)
+	}
+	if fv.Kind() == reflect.Ptr {
+		if !c.hasValues(values, name, typ) {
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(typ))
+		}
+		fv = fv.Elem()
+	}
+	return c.decodeStruct(values, fv, name, path, presence)
+}
+
+// decodeValue stores the values of the key name into fv.
+// fv is left untouched if there are no values for name.
+func (c *Converter) decodeValue(values url.Values, name string, fv reflect.Value) error {
+	if !fv.CanSet() {
+		return nil
+	}
+	if fv.CanAddr() && fv.Addr().Type().Implements(decoderType) {
+		vs, ok := values[name]
+		if !ok {
+			return nil
+		}
+		return fv.Addr().Interface().(Decoder).Decode(vs)
+	}
+	if fv.CanAddr() && fv.Addr().Type().Implements(optionalSetterType) {
+		value, present, null := fv.Addr().Interface().(optionalSetter).optionalSet()
+		if !c.hasValues(values, name, value.Type()) {
+			return nil
+		}
+		*present = true
+		if vs := values[name]; null != nil && !c.style.SkipNull && len(vs) == 1 && vs[0] == c.style.Null {
+			*null = true
+			return nil
+		}
+		return c.decodeValue(values, name, value)
+	}
+
+	if fv.CanAddr() && fv.Kind() != reflect.Ptr && fv.Addr().Type().Implements(scannerType) {
+		vs, ok := values[name]
+		if !ok || len(vs) == 0 {
+			return nil
+		}
+		return c.scan(fv.Addr().Interface().(sql.Scanner), vs[0])
+	}
+
+	switch {
+	case fv.Kind() == reflect.Ptr:
+		if !c.hasValues(values, name, fv.Type().Elem()) {
+			return nil
+		}
+		if vs := values[name]; !c.style.SkipNull && len(vs) == 1 && vs[0] == c.style.Null {
+			fv.Set(reflect.Zero(fv.Type()))
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return c.decodeValue(values, name, fv.Elem())
+	case fv.Type() == timeType:
+		vs, ok := values[name]
+		if !ok || len(vs) == 0 {
+			return nil
+		}
+		return c.setString(fv, vs[0])
+	case fv.Kind() == reflect.Struct:
+		if c.style.Nest == NestNone {
+			return nil
+		}
+		return c.decodeStruct(values, fv, name, "", nil)
+	case fv.Kind() == reflect.Map:
+		if c.style.Nest == NestNone {
+			return nil
+		}
+		return c.decodeMap(values, name, fv)
+	case fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array:
+		return c.decodeList(values, name, fv)
+	default:
+		vs, ok := values[name]
+		if !ok || len(vs) == 0 {
+			return nil
+		}
+		return c.setString(fv, vs[0])
+	}
+}
+
+// hasValues reports whether values hold anything for a value of type typ under name.
+func (c *Converter) hasValues(values url.Values, name string, typ reflect.Type) bool {
+	if _, ok := values[name]; ok {
+		return true
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if elem, ok := optionalElem(typ); ok {
+		return c.hasValues(values, name, elem)
+	}
+	switch {
+	case typ == timeType:
+		return false
+	case typ.Kind() == reflect.Struct || typ.Kind() == reflect.Map:
+		if c.style.Nest == NestNone {
+			return false
+		}
+		prefix := c.style.nestKey(name, "")
+		prefix = strings.TrimSuffix(prefix, "]")
+		for k := range values {
+			if strings.HasPrefix(k, prefix) {
+				return true
+			}
+		}
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		_, brackets := values[name+"[]"]
+		_, indices := values[name+"[0]"]
+		return brackets || indices
+	}
+	return false
+}
+
+// listValues returns the elements of the list name according to the array format of the style.
+func (c *Converter) listValues(values url.Values, name string) []string {
+	switch c.style.Array {
+	case ArrayBrackets:
+		return values[c.style.arrayKey(name, 0)]
+	case ArrayIndices:
+		var list []string
+		for i := 0; ; i++ {
+			vs, ok := values[c.style.arrayKey(name, i)]
+			if !ok || len(vs) == 0 {
+				return list
+			}
+			list = append(list, vs[0])
+		}
+	case ArrayComma:
+		vs := values[name]
+		if len(vs) == 0 {
+			return nil
+		}
+		if vs[0] == "" {
+			return []string{}
+		}
+		return strings.Split(vs[0], ",")
+	default:
+		return values[name]
+	}
+}
+
+func (c *Converter) decodeList(values url.Values, name string, fv reflect.Value) error {
+	list := c.listValues(values, name)
+	if list == nil {
+		return nil
+	}
+	if fv.Kind() == reflect.Slice {
+		fv.Set(reflect.MakeSlice(fv.Type(), len(list), len(list)))
+	} else if len(list) > fv.Len() {
+		return fmt.Errorf("%d values for array of length %d", len(list), fv.Len())
+	}
+	for i, s := range list {
+		elem := fv.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			elem.Set(reflect.New(elem.Type().Elem()))
+			elem = elem.Elem()
+		}
+		if err := c.setString(elem, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeMap stores the nested keys of name into the map fv.
+// Only maps with string keys and primitive values are supported.
+func (c *Converter) decodeMap(values url.Values, name string, fv reflect.Value) error {
+	typ := fv.Type()
+	if typ.Key().Kind() != reflect.String {
+		return fmt.Errorf("map key must be a string")
+	}
+	prefix := strings.TrimSuffix(c.style.nestKey(name, ""), "]")
+	for k, vs := range values {
+		if !strings.HasPrefix(k, prefix) || len(vs) == 0 {
+			continue
+		}
+		key := k[len(prefix):]
+		if c.style.Nest == NestBrackets {
+			if !strings.HasSuffix(key, "]") {
+				continue
+			}
+			key = key[:len(key)-1]
+		}
+		if strings.ContainsAny(key, ".[]") {
+			continue
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.MakeMap(typ))
+		}
+		elem := reflect.New(typ.Elem()).Elem()
+		if err := c.setString(elem, vs[0]); err != nil {
+			return fmt.Errorf("key %q: %w", k, err)
+		}
+		fv.SetMapIndex(reflect.ValueOf(key).Convert(typ.Key()), elem)
+	}
+	return nil
+}
+
+// scan stores s into a sql.Scanner such as the database/sql Null types.
+// The Null value of the style is scanned as nil unless nil values are skipped.
+// Values that cannot be scanned as a string are retried as a time.Time.
+func (c *Converter) scan(scanner sql.Scanner, s string) error {
+	if !c.style.SkipNull && s == c.style.Null {
+		return scanner.Scan(nil)
+	}
+	err := scanner.Scan(s)
+	if err == nil {
+		return nil
+	}
+	if t, terr := time.Parse(time.RFC3339Nano, s); terr == nil {
+		return scanner.Scan(t)
+	}
+	return err
+}
+
+// setString parses s into the primitive value, time.Time or sql.Scanner fv.
+func (c *Converter) setString(fv reflect.Value, s string) error {
+	if fv.CanAddr() && fv.Addr().Type().Implements(decoderType) {
+		return fv.Addr().Interface().(Decoder).Decode([]string{s})
+	}
+	if fv.CanAddr() && fv.Addr().Type().Implements(scannerType) {
+		return c.scan(fv.Addr().Interface().(sql.Scanner), s)
+	}
+	if fv.Type() == timeType {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		switch s {
+		case c.style.True:
+			fv.SetBool(true)
+		case c.style.False:
+			fv.SetBool(false)
+		default:
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+			fv.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
+
+// Decode stores url.Values into the struct pointed to by dst.
+// The fields must have a tag with the key "url".
+func Decode(values url.Values, dst interface{}, opts ...CallOption) error {
+	converter := NewConverter(NewTag())
+	return converter.Decode(values, dst, opts...)
+}
@@ -0,0 +1,104 @@
+package querystring
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type decodeUpper string
+
+func (d *decodeUpper) Decode(values []string) error {
+	*d = decodeUpper(strings.ToUpper(strings.Join(values, "-")))
+	return nil
+}
+
+func TestDecode(t *testing.T) {
+	type Input struct {
+		Name   string      `url:"name"`
+		Count  int         `url:"count"`
+		Ratio  float64     `url:"ratio"`
+		Active bool        `url:"active"`
+		Tags   []string    `url:"tags"`
+		IDs    [2]uint     `url:"ids"`
+		Limit  *int        `url:"limit"`
+		Offset *int        `url:"offset"`
+		Time   time.Time   `url:"time"`
+		Upper  decodeUpper `url:"upper"`
+		Skip   string      `url:"-"`
+	}
+	now := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	values := url.Values{
+		"name":   {"alice"},
+		"count":  {"3"},
+		"ratio":  {"0.5"},
+		"active": {"true"},
+		"tags":   {"a", "b"},
+		"ids":    {"1", "2"},
+		"limit":  {"10"},
+		"time":   {now.Format(time.RFC3339Nano)},
+		"upper":  {"x", "y"},
+		"Skip":   {"no"},
+		"other":  {"ignored"},
+	}
+	var got Input
+	if err := Decode(values, &got); err != nil {
+		t.Fatal(err)
+	}
+	limit := 10
+	expected := Input{
+		Name:   "alice",
+		Count:  3,
+		Ratio:  0.5,
+		Active: true,
+		Tags:   []string{"a", "b"},
+		IDs:    [2]uint{1, 2},
+		Limit:  &limit,
+		Time:   now,
+		Upper:  "X-Y",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestDecodeStyleRoundTrip(t *testing.T) {
+	in := styleInput{
+		Name:    "alice",
+		Tags:    []string{"a", "b"},
+		Active:  true,
+		Address: styleAddress{City: "Paris", Zip: "75001"},
+	}
+	for _, style := range []Style{StyleDefault, StylePHP, StyleQS, StyleRails, StyleOpenAPIDeepObject} {
+		con := NewConverter(NewTag(), WithStyle(style))
+		values, err := con.Values(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out styleInput
+		if err := con.Decode(values, &out); err != nil {
+			t.Fatal(err)
+		}
+		expected := in
+		if style.Nest == NestNone {
+			expected.Address = styleAddress{}
+		}
+		if !reflect.DeepEqual(out, expected) {
+			t.Errorf("%+v: expected %+v, got %+v", style, expected, out)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	type Input struct {
+		Count int `url:"count"`
+	}
+	if err := Decode(url.Values{"count": {"x"}}, &Input{}); err == nil {
+		t.Error("expected an error for an invalid integer")
+	}
+	if err := Decode(url.Values{}, Input{}); err == nil {
+		t.Error("expected an error for a non-pointer destination")
+	}
+}
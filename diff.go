@@ -0,0 +1,240 @@
+package querystring
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+)
+
+// Diff returns the parameters of current whose encoded values differ from base,
+// such as the filters changed from their defaults.
+// base and current must be structs, or pointers to structs, of the same type;
+// a nil pointer is taken as the zero value. Both are encoded with the same field
+// plan. Scalars, the fields of nested structs and the entries of maps are compared
+// key by key, so that only the keys whose values differ are returned. A list is
+// returned whole when any of its elements changed, so that the result decodes.
+func (c *Converter) Diff(base, current interface{}) (url.Values, error) {
+	changed, _, err := c.diff(base, current)
+	return changed, err
+}
+
+// DiffRemoved is like Diff, but also returns the sorted keys that base encodes
+// and current does not, such as a filter reset to an empty value.
+func (c *Converter) DiffRemoved(base, current interface{}) (url.Values, []string, error) {
+	return c.diff(base, current)
+}
+
+// diffResult collects the changed values and the removed keys of a diff.
+type diffResult struct {
+	changed url.Values
+	removed []string
+}
+
+// addAll records the values of after whole if they differ from before.
+func (d *diffResult) addAll(before, after url.Values) {
+	if sameValues(before, after) {
+		return
+	}
+	for k, vs := range after {
+		d.changed[k] = vs
+	}
+	d.addRemoved(before, after)
+}
+
+// addKeys records the keys of after whose values differ from before.
+func (d *diffResult) addKeys(before, after url.Values) {
+	for k, vs := range after {
+		if !equalValues(before[k], vs, false) {
+			d.changed[k] = vs
+		}
+	}
+	d.addRemoved(before, after)
+}
+
+func (d *diffResult) addRemoved(before, after url.Values) {
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			d.removed = append(d.removed, k)
+		}
+	}
+}
+
+func (c *Converter) diff(base, current interface{}) (url.Values, []string, error) {
+	bv, cv, err := diffValues(base, current)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := c.plan(cv.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+	d := &diffResult{changed: make(url.Values)}
+	if err := c.diffFields(d, bv, cv, "", p.fields); err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(d.removed)
+	return d.changed, d.removed, nil
+}
+
+// diffFields compares the fields of the structs bv and cv, nested in scope.
+func (c *Converter) diffFields(d *diffResult, bv, cv reflect.Value, scope string, fields []field) error {
+	for _, f := range fields {
+		before, after := make(url.Values), make(url.Values)
+		if err := c.encodeFields(before, bv, scope, []field{f}); err != nil {
+			return err
+		}
+		if err := c.encodeFields(after, cv, scope, []field{f}); err != nil {
+			return err
+		}
+		if sameValues(before, after) {
+			continue
+		}
+		if f.opts.Contains("remain") {
+			d.addKeys(before, after)
+			continue
+		}
+		bf, bok := fieldValue(bv, f.index, false)
+		cf, cok := fieldValue(cv, f.index, false)
+		if _, styled, _ := parseParamStyle(f.opts); styled || !bok || !cok {
+			d.addAll(before, after)
+			continue
+		}
+		if err := c.diffValue(d, c.style.nestKey(scope, f.name), bf, cf, before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffValue compares bv and cv, encoded under name as before and after.
+// Nested structs and maps are compared key by key; other values whole.
+func (c *Converter) diffValue(d *diffResult, name string, bv, cv reflect.Value, before, after url.Values) error {
+	bu, bok := c.nested(bv)
+	cu, cok := c.nested(cv)
+	if !bok || !cok || bu.Type() != cu.Type() {
+		d.addAll(before, after)
+		return nil
+	}
+	if bu.Kind() == reflect.Struct {
+		p, err := c.plan(bu.Type())
+		if err != nil {
+			return err
+		}
+		return c.diffFields(d, bu, cu, name, p.fields)
+	}
+	keys := make(map[string]bool)
+	for _, k := range append(bu.MapKeys(), cu.MapKeys()...) {
+		keys[k.String()] = true
+	}
+	for k := range keys {
+		key, err := c.mapKey(k)
+		if err != nil {
+			return fmt.Errorf("map key %q: %w", k, err)
+		}
+		key = c.style.nestKey(name, key)
+		mk := reflect.ValueOf(k).Convert(bu.Type().Key())
+		be, ce := bu.MapIndex(mk), cu.MapIndex(mk)
+		eb, ea := make(url.Values), make(url.Values)
+		if be.IsValid() {
+			if err := c.encodeValue(eb, key, be); err != nil {
+				return err
+			}
+		}
+		if ce.IsValid() {
+			if err := c.encodeValue(ea, key, ce); err != nil {
+				return err
+			}
+		}
+		if !be.IsValid() || !ce.IsValid() {
+			d.addAll(eb, ea)
+			continue
+		}
+		if err := c.diffValue(d, key, be, ce, eb, ea); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nested returns the struct or map that encodeValue nests the keys of v under,
+// unwrapping present values, pointers and interfaces. The second return value
+// is false if v is encoded as a list or as values of its own key.
+func (c *Converter) nested(v reflect.Value) (reflect.Value, bool) {
+	for {
+		if v.Kind() == reflect.Struct && v.Type().Implements(optionalType) {
+			value, present, null := v.Interface().(optional).optionalGet()
+			if !present || null {
+				return reflect.Value{}, false
+			}
+			v = value
+			continue
+		}
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			return reflect.Value{}, false
+		}
+		if v.Type().Implements(encoderType) {
+			return reflect.Value{}, false
+		}
+		if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface && v.Type().Implements(valuerType) {
+			return reflect.Value{}, false
+		}
+		if _, ok := c.formatValue(v); ok {
+			return reflect.Value{}, false
+		}
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			v = v.Elem()
+		case reflect.Struct, reflect.Map:
+			return v, c.style.Nest != NestNone
+		default:
+			return reflect.Value{}, false
+		}
+	}
+}
+
+// sameValues reports whether a and b hold the same values in the same order.
+func sameValues(a, b url.Values) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, vs := range a {
+		if !equalValues(vs, b[k], false) {
+			return false
+		}
+	}
+	return true
+}
+
+// diffValues returns the structs base and current point to, which must be of the same type.
+func diffValues(base, current interface{}) (reflect.Value, reflect.Value, error) {
+	bt, ct := reflect.TypeOf(base), reflect.TypeOf(current)
+	if bt == nil || ct == nil || bt != ct {
+		return reflect.Value{}, reflect.Value{}, fmt.Errorf("diff values must have the same type, got %T and %T", base, current)
+	}
+	typ := bt
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return reflect.Value{}, reflect.Value{}, fmt.Errorf("unsupported type %T", base)
+	}
+	deref := func(v interface{}) reflect.Value {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return reflect.New(typ).Elem()
+			}
+			rv = rv.Elem()
+		}
+		return rv
+	}
+	return deref(base), deref(current), nil
+}
+
+// Diff returns the parameters of current whose encoded values differ from base.
+// The fields must have a tag with the key "url".
+func Diff(base, current interface{}) (url.Values, error) {
+	converter := NewConverter(NewTag())
+	return converter.Diff(base, current)
+}
@@ -0,0 +1,120 @@
+package querystring
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	type Filter struct {
+		Status string `url:"status"`
+		Owner  string `url:"owner"`
+	}
+	type Input struct {
+		Page   int      `url:"page"`
+		Limit  int      `url:"limit"`
+		Sort   string   `url:"sort,omitempty"`
+		Tags   []string `url:"tags"`
+		Filter Filter   `url:"filter"`
+	}
+	defaults := Input{Page: 1, Limit: 20, Sort: "name", Tags: []string{"a", "b"}, Filter: Filter{Status: "open"}}
+	current := defaults
+	current.Limit = 50
+	current.Sort = ""
+	current.Tags = []string{"a", "c"}
+	current.Filter.Owner = "me"
+
+	c := NewConverter(NewTag(), WithStyle(StyleQS))
+	if _, _, err := c.DiffRemoved(defaults, &current); err == nil {
+		t.Fatal("expected an error for values of different types")
+	}
+	changed, removed, err := c.DiffRemoved(defaults, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := url.Values{
+		"limit":         {"50"},
+		"tags[0]":       {"a"},
+		"tags[1]":       {"c"},
+		"filter[owner]": {"me"},
+	}
+	if !reflect.DeepEqual(changed, expect) {
+		t.Errorf("expected %v, got %v", expect, changed)
+	}
+	if !reflect.DeepEqual(removed, []string{"sort"}) {
+		t.Errorf("expected removed keys [sort], got %v", removed)
+	}
+
+	current = defaults
+	current.Tags = []string{"a"}
+	changed, removed, err = c.DiffRemoved(&defaults, &current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changed, url.Values{"tags[0]": {"a"}}) || !reflect.DeepEqual(removed, []string{"tags[1]"}) {
+		t.Errorf("expected tags[0] and tags[1] to be removed, got %v and %v", changed, removed)
+	}
+
+	changed, err = Diff(&defaults, &defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("expected no changes, got %v", changed)
+	}
+	changed, err = Diff((*Input)(nil), &Input{Page: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changed, url.Values{"page": {"3"}}) {
+		t.Errorf("unexpected changes against a nil base %v", changed)
+	}
+}
+
+func TestDiffDecodes(t *testing.T) {
+	type Filter struct {
+		Status string   `url:"status"`
+		Labels []string `url:"labels"`
+	}
+	type Input struct {
+		Limit   int               `url:"limit"`
+		Tags    []string          `url:"tags"`
+		Filter  Filter            `url:"filter"`
+		Options map[string]string `url:"options"`
+	}
+	defaults := Input{
+		Limit:   20,
+		Tags:    []string{"a", "b", "c"},
+		Filter:  Filter{Status: "open", Labels: []string{"x", "y"}},
+		Options: map[string]string{"color": "red", "size": "m"},
+	}
+	current := defaults
+	current.Tags = []string{"a", "b", "d"}
+	current.Filter = Filter{Status: "open", Labels: []string{"x", "z"}}
+	current.Options = map[string]string{"color": "blue", "size": "m"}
+
+	for _, style := range []Style{StyleQS, StyleRails, StylePHP} {
+		c := NewConverter(NewTag(), WithStyle(style))
+		changed, err := c.Diff(defaults, current)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := changed[c.style.nestKey("filter", "status")]; ok {
+			t.Errorf("%+v: unexpected unchanged filter status in %v", style, changed)
+		}
+		if _, ok := changed[c.style.nestKey("options", "size")]; ok {
+			t.Errorf("%+v: unexpected unchanged size option in %v", style, changed)
+		}
+		var out Input
+		if err := c.Decode(changed, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out.Tags, current.Tags) || !reflect.DeepEqual(out.Filter.Labels, current.Filter.Labels) {
+			t.Errorf("%+v: expected the changed lists, got %+v", style, out)
+		}
+		if out.Options["color"] != "blue" {
+			t.Errorf("%+v: expected the changed color option, got %+v", style, out.Options)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package querystring
+
+import "strings"
+
+// percentEncode percent-encodes s as defined by RFC 3986, with uppercase hexadecimal digits.
+// Unreserved characters are always kept; reserved characters and existing
+// percent-encoded triplets are kept when allowReserved is set.
+func percentEncode(s string, allowReserved bool) string {
+	const hex = "0123456789ABCDEF"
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isUnreserved(c):
+			builder.WriteByte(c)
+		case allowReserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0:
+			builder.WriteByte(c)
+		case allowReserved && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			builder.WriteString(s[i : i+3])
+			i += 2
+		default:
+			builder.WriteByte('%')
+			builder.WriteByte(hex[c>>4])
+			builder.WriteByte(hex[c&15])
+		}
+	}
+	return builder.String()
+}
+
+// isUnreserved reports whether c is an unreserved character of RFC 3986.
+func isUnreserved(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
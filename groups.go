@@ -0,0 +1,28 @@
+package querystring
+
+import "strings"
+
+// fieldGroups returns the groups of a field, selected with the tag option
+// groups=<name>|<name>. A field without groups belongs to every view.
+func fieldGroups(opts TagOptions) []string {
+	value, ok := opts.Value("groups")
+	if !ok || value == "" {
+		return nil
+	}
+	return strings.Split(value, "|")
+}
+
+// inGroups reports whether a field with the tag options opts is visible with
+// the active groups of the Converter: it has no groups or shares one with them.
+func (c *Converter) inGroups(opts TagOptions) bool {
+	groups := fieldGroups(opts)
+	if len(groups) == 0 {
+		return true
+	}
+	for _, g := range groups {
+		if containsString(c.groups, g) {
+			return true
+		}
+	}
+	return false
+}
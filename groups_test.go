@@ -0,0 +1,70 @@
+package querystring
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	type Input struct {
+		Query string `url:"q"`
+		Debug bool   `url:"debug,groups=admin|internal"`
+		Trace string `url:"trace,groups=internal"`
+	}
+	in := Input{Query: "go", Debug: true, Trace: "abc"}
+
+	tests := []struct {
+		groups []string
+		expect url.Values
+	}{
+		{nil, url.Values{"q": {"go"}}},
+		{[]string{"admin"}, url.Values{"q": {"go"}, "debug": {"true"}}},
+		{[]string{"internal"}, url.Values{"q": {"go"}, "debug": {"true"}, "trace": {"abc"}}},
+		{[]string{"public", "admin"}, url.Values{"q": {"go"}, "debug": {"true"}}},
+	}
+	for _, test := range tests {
+		c := NewConverter(NewTag(), WithGroups(test.groups...))
+		values, err := c.Values(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(values, test.expect) {
+			t.Errorf("groups %v: expected %v, got %v", test.groups, test.expect, values)
+		}
+	}
+
+	query := url.Values{"q": {"go"}, "debug": {"true"}, "trace": {"abc"}}
+	var out Input
+	if err := NewConverter(NewTag()).Decode(query, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != (Input{Query: "go"}) {
+		t.Errorf("public decoding bound admin parameters: %+v", out)
+	}
+	c := NewConverter(NewTag(), WithUnknownKeys(RejectUnknownKeys))
+	if err := c.Decode(query, &Input{}); err == nil {
+		t.Error("expected an unknown keys error for admin parameters")
+	}
+	out = Input{}
+	if err := NewConverter(NewTag(), WithGroups("admin")).Decode(query, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != (Input{Query: "go", Debug: true}) {
+		t.Errorf("unexpected decoded value %+v", out)
+	}
+}
+
+func TestGroupsSharedKey(t *testing.T) {
+	type Input struct {
+		Public   string `url:"view,groups=public"`
+		Internal string `url:"view,groups=internal"`
+	}
+	values, err := NewConverter(NewTag(), WithGroups("internal")).Values(Input{Public: "a", Internal: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("view") != "b" {
+		t.Errorf("expected the internal field, got %v", values)
+	}
+}
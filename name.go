@@ -1,6 +1,7 @@
 package querystring
 
 import (
+	"fmt"
 	"strings"
 	"unicode"
 )
@@ -8,40 +9,156 @@ import (
 type Name string
 
 const (
-	CamelCase  Name = "camel"
-	PascalCase Name = "pascal"
-	SnakeCase  Name = "snake"
+	CamelCase          Name = "camel"
+	PascalCase         Name = "pascal"
+	SnakeCase          Name = "snake"
+	KebabCase          Name = "kebab"
+	ScreamingSnakeCase Name = "screaming_snake"
+	DotCase            Name = "dot"
+	TrainCase          Name = "train"
+	FlatCase           Name = "flat"
 )
 
+// NameConverter converts the Go name of a field without a tag to its key.
+// Name implements NameConverter without acronyms.
+type NameConverter interface {
+	Convert(name string) string
+}
+
+// NameConverterFunc adapts an ordinary function to a NameConverter.
+type NameConverterFunc func(name string) string
+
+// Convert returns f(name).
+func (f NameConverterFunc) Convert(name string) string {
+	return f(name)
+}
+
 func (n Name) IsEmpty() bool {
 	return n == ""
 }
 
+// known reports whether n is one of the Name constants.
+func (n Name) known() bool {
+	switch n {
+	case CamelCase, PascalCase, SnakeCase, KebabCase, ScreamingSnakeCase, DotCase, TrainCase, FlatCase:
+		return true
+	}
+	return false
+}
+
+// NameError reports a name that cannot be converted to the case of a Name.
+type NameError struct {
+	Name  Name
+	Input string
+}
+
+func (e *NameError) Error() string {
+	if !e.Name.known() {
+		return fmt.Sprintf("unknown naming strategy %q", string(e.Name))
+	}
+	return fmt.Sprintf("cannot convert %q to %s case", e.Input, e.Name)
+}
+
+// Acronyms is a set of words, in upper case, that CamelCase, PascalCase and
+// TrainCase render entirely in upper case, such as "ID" in "userID" for "user_id".
+// Words that are not in the set are rendered in lower case, except for their
+// first letter. A nil Acronyms keeps the case of the words instead, so that an
+// empty set, NewAcronyms(), renders "UserID" as "userId".
+type Acronyms map[string]bool
+
+// NewAcronyms returns the set of the words.
+func NewAcronyms(words ...string) Acronyms {
+	acronyms := make(Acronyms, len(words))
+	for _, w := range words {
+		acronyms[strings.ToUpper(w)] = true
+	}
+	return acronyms
+}
+
+// DefaultAcronyms is a set of common acronyms, enabled with WithAcronyms(DefaultAcronyms).
+// Convert and the To*Case functions do not use any acronyms.
+var DefaultAcronyms = NewAcronyms(
+	"API", "ASCII", "CPU", "CSS", "DNS", "HTML", "HTTP", "HTTPS", "ID", "IP",
+	"JSON", "SQL", "TCP", "TLS", "UDP", "UI", "URI", "URL", "UUID", "XML",
+)
+
 // Convert : Converts the string name to the specified case.
 // The function returns the converted string.
 // For example:
 // Name("camel").Convert("hello_world") returns "helloWorld".
 // Name("pascal").Convert("hello_world") returns "HelloWorld".
 // Name("snake").Convert("HelloWorld") returns "hello_world".
+// Name("kebab").Convert("HelloWorld") returns "hello-world".
+// Name("screaming_snake").Convert("HelloWorld") returns "HELLO_WORLD".
+// Name("dot").Convert("HelloWorld") returns "hello.world".
+// Name("train").Convert("hello_world") returns "Hello-World".
+// Name("flat").Convert("HelloWorld") returns "helloworld".
 // Name("").Convert("hello_world") returns "hello_world".
 func (n Name) Convert(name string) string {
+	return n.ConvertWith(name, nil)
+}
+
+// ConvertWith : Converts the string name to the specified case, rendering
+// the words of acronyms in upper case.
+// For example:
+// Name("camel").ConvertWith("user_id", NewAcronyms("ID")) returns "userID".
+// Name("camel").ConvertWith("user_id", nil) returns "userId".
+// Name("camel").ConvertWith("UserID", nil) returns "userID".
+// Name("camel").ConvertWith("UserID", NewAcronyms()) returns "userId".
+func (n Name) ConvertWith(name string, acronyms Acronyms) string {
 	var tag string
 	switch n {
 	case CamelCase:
-		tag = ToCamelCase(name)
+		tag = toCamelCase(name, acronyms, false)
 	case PascalCase:
-		tag = ToPascalCase(name)
+		tag = toCamelCase(name, acronyms, true)
 	case SnakeCase:
-		tag = ToSnakeCase(name)
+		tag = joinWords(name, "_", strings.ToLower)
+	case KebabCase:
+		tag = joinWords(name, "-", strings.ToLower)
+	case ScreamingSnakeCase:
+		tag = joinWords(name, "_", strings.ToUpper)
+	case DotCase:
+		tag = joinWords(name, ".", strings.ToLower)
+	case TrainCase:
+		tag = joinWords(name, "-", func(w string) string { return titleWord(w, acronyms) })
+	case FlatCase:
+		tag = joinWords(name, "", strings.ToLower)
 	default:
 		tag = name
 	}
 	return tag
 }
 
+// TryConvert : Converts the string name to the specified case like Convert,
+// but returns a *NameError instead of an empty string if name cannot be converted
+// or n is not one of the Name constants.
+// The empty Name returns name unchanged.
+// For example:
+// Name("snake").TryConvert("HelloWorld") returns "hello_world", nil.
+// Name("camel").TryConvert("Type_") returns "", &NameError{Name: "camel", Input: "Type_"}.
+func (n Name) TryConvert(name string) (string, error) {
+	return n.TryConvertWith(name, nil)
+}
+
+// TryConvertWith : Converts the string name like TryConvert, rendering the words of acronyms in upper case.
+func (n Name) TryConvertWith(name string, acronyms Acronyms) (string, error) {
+	if n.IsEmpty() {
+		return name, nil
+	}
+	if !n.known() {
+		return "", &NameError{Name: n, Input: name}
+	}
+	converted := n.ConvertWith(name, acronyms)
+	if converted == "" {
+		return "", &NameError{Name: n, Input: name}
+	}
+	return converted, nil
+}
+
 // ToCamelCase : Converts the string s to camel case.
 // The string s must start with a letter and contain only letters, numbers, and the characters '_', '-', and '.'.
-// The first letter of the string s is converted to lowercase.
+// The first word of the string s is converted to lowercase; the other words keep their case, except for their first letter.
 // If the string s is empty or does not start with a letter, the function returns an empty string.
 // If the string s contains characters other than letters, numbers, '_', '-', and '.', the function returns an empty string.
 // The function returns the converted string.
@@ -50,45 +167,19 @@ func (n Name) Convert(name string) string {
 // ToCamelCase("foo_bar") returns "fooBar".
 // ToCamelCase("foo.bar") returns "fooBar".
 // ToCamelCase("foo-bar-") returns "".
-// ToCamelCase("foo-bar-1") returns "".
+// ToCamelCase("foo-bar-1") returns "fooBar1".
 // ToCamelCase("1foo-bar") returns "".
 // ToCamelCase("foobar") returns "foobar".
 // ToCamelCase("fooBar") returns "fooBar".
+// ToCamelCase("UserID") returns "userID".
+// ToCamelCase("HTTPServer") returns "httpServer".
 func ToCamelCase(s string) string {
-	var l = len(s)
-	if l == 0 {
-		return ""
-	}
-	if !unicode.IsLetter(rune(s[0])) ||
-		(!unicode.IsLetter(rune(s[l-1])) && !unicode.IsNumber(rune(s[l-1]))) {
-		return ""
-	}
-
-	var builder strings.Builder
-	var isSeparator bool
-	builder.WriteRune(unicode.ToLower(rune(s[0])))
-	for i := 1; i < l; i++ {
-		r := rune(s[i])
-		if !checkCompliance(r) {
-			return ""
-		}
-		if checkSpeChars(r) {
-			isSeparator = true
-			continue
-		}
-		if isSeparator {
-			builder.WriteRune(unicode.ToUpper(r))
-			isSeparator = false
-			continue
-		}
-		builder.WriteRune(r)
-	}
-	return builder.String()
+	return toCamelCase(s, nil, false)
 }
 
 // ToPascalCase : Converts the string s to pascal case.
 // The string s must start with a letter and contain only letters, numbers, and the characters '_', '-', and '.'.
-// The first letter of the string s is converted to uppercase.
+// The first letter of every word is converted to uppercase; the other letters keep their case.
 // If the string s is empty or does not start with a letter, the function returns an empty string.
 // If the string s contains characters other than letters, numbers, '_', '-', and '.', the function returns an empty string.
 // The function returns the converted string.
@@ -97,47 +188,22 @@ func ToCamelCase(s string) string {
 // ToPascalCase("foo_bar") returns "FooBar".
 // ToPascalCase("foo.bar") returns "FooBar".
 // ToPascalCase("foo-bar-") returns "".
-// ToPascalCase("foo-bar-1") returns "".
+// ToPascalCase("foo-bar-1") returns "FooBar1".
 // ToPascalCase("1foo-bar") returns "".
 // ToPascalCase("foobar") returns "Foobar".
-// ToPascalCase("fooBar") returns "Foobar".
-// ToPascalCase("fooBar1") returns "Foobar1".
+// ToPascalCase("fooBar") returns "FooBar".
+// ToPascalCase("fooBar1") returns "FooBar1".
 // ToPascalCase("FooBar") returns "FooBar".
 // ToPascalCase("foo_2-bar") returns "Foo2Bar".
+// ToPascalCase("user_id") returns "UserId".
+// ToPascalCase("ApiKey") returns "ApiKey".
 func ToPascalCase(s string) string {
-	var l = len(s)
-	if l == 0 {
-		return ""
-	}
-	if !unicode.IsLetter(rune(s[0])) ||
-		(!unicode.IsLetter(rune(s[l-1])) && !unicode.IsNumber(rune(s[l-1]))) {
-		return ""
-	}
-	var builder strings.Builder
-	var isSeparator bool
-	builder.WriteRune(unicode.ToUpper(rune(s[0])))
-	for i := 1; i < len(s); i++ {
-		r := rune(s[i])
-		if !checkCompliance(r) {
-			return ""
-		}
-		if isSeparator {
-			builder.WriteRune(unicode.ToUpper(r))
-			isSeparator = false
-			continue
-		}
-		if checkSpeChars(r) {
-			isSeparator = true
-			continue
-		}
-		builder.WriteRune(r)
-	}
-	return builder.String()
+	return toCamelCase(s, nil, true)
 }
 
 // ToSnakeCase : Converts the string s to snake case.
 // The string s must start with a letter and contain only letters, numbers, and the characters '_', '-', and '.'.
-// The first letter of the string s is converted to lowercase.
+// Every word is converted to lowercase; runs of capitals are kept together as one word.
 // If the string s is empty or does not start with a letter, the function returns an empty string.
 // If the string s contains characters other than letters, numbers, '_', '-', and '.', the function returns an empty string.
 // The function returns the converted string.
@@ -148,41 +214,163 @@ func ToPascalCase(s string) string {
 // ToSnakeCase("foo-bar-") returns "".
 // ToSnakeCase("foo-bar-1") returns "foo_bar_1".
 // ToSnakeCase("1foo-bar") returns "".
+// ToSnakeCase("UserID") returns "user_id".
+// ToSnakeCase("HTTPServer") returns "http_server".
 func ToSnakeCase(input string) string {
-	if len(input) == 0 {
-		return ""
-	}
-	var l = len(input)
-	if !unicode.IsLetter(rune(input[0])) ||
-		(!unicode.IsLetter(rune(input[l-1])) && !unicode.IsNumber(rune(input[l-1]))) {
-		return ""
-	}
-	var result strings.Builder
-	var isSeparator bool
-	for i, char := range input {
-		if i == 0 {
-			result.WriteRune(unicode.ToLower(char))
+	return joinWords(input, "_", strings.ToLower)
+}
+
+// ToKebabCase : Converts the string s to kebab case.
+// The words of the string s are split as in ToSnakeCase and joined with '-'.
+// For example:
+// ToKebabCase("HelloWorld") returns "hello-world".
+// ToKebabCase("UserID") returns "user-id".
+// ToKebabCase("foo-bar-") returns "".
+func ToKebabCase(s string) string {
+	return joinWords(s, "-", strings.ToLower)
+}
+
+// ToScreamingSnakeCase : Converts the string s to screaming snake case.
+// The words of the string s are split as in ToSnakeCase, converted to uppercase and joined with '_'.
+// For example:
+// ToScreamingSnakeCase("HelloWorld") returns "HELLO_WORLD".
+// ToScreamingSnakeCase("hello2world") returns "HELLO_2_WORLD".
+func ToScreamingSnakeCase(s string) string {
+	return joinWords(s, "_", strings.ToUpper)
+}
+
+// ToDotCase : Converts the string s to dot case.
+// The words of the string s are split as in ToSnakeCase and joined with '.'.
+// For example:
+// ToDotCase("HelloWorld") returns "hello.world".
+// ToDotCase("HTTPServer") returns "http.server".
+func ToDotCase(s string) string {
+	return joinWords(s, ".", strings.ToLower)
+}
+
+// ToTrainCase : Converts the string s to train case.
+// The words of the string s are split as in ToSnakeCase, capitalized and joined with '-'.
+// For example:
+// ToTrainCase("hello_world") returns "Hello-World".
+// ToTrainCase("UserID") returns "User-ID".
+func ToTrainCase(s string) string {
+	return joinWords(s, "-", func(w string) string { return titleWord(w, nil) })
+}
+
+// ToFlatCase : Converts the string s to flat case.
+// The words of the string s are split as in ToSnakeCase and joined without separator.
+// For example:
+// ToFlatCase("HelloWorld") returns "helloworld".
+// ToFlatCase("user_id") returns "userid".
+func ToFlatCase(s string) string {
+	return joinWords(s, "", strings.ToLower)
+}
+
+func toCamelCase(s string, acronyms Acronyms, pascal bool) string {
+	words := splitWords(s)
+	var builder strings.Builder
+	for i, w := range words {
+		switch {
+		case i == 0 && !pascal:
+			builder.WriteString(strings.ToLower(w.text))
+		case w.joined:
+			builder.WriteString(strings.ToLower(w.text))
+		default:
+			builder.WriteString(titleWord(w.text, acronyms))
+		}
+	}
+	return builder.String()
+}
+
+// joinWords splits s into words, transforms every word and joins them with sep.
+func joinWords(s, sep string, transform func(string) string) string {
+	words := splitWords(s)
+	parts := make([]string, len(words))
+	for i, w := range words {
+		parts[i] = transform(w.text)
+	}
+	return strings.Join(parts, sep)
+}
+
+// titleWord returns w with its first letter in upper case. A nil acronyms keeps
+// the rest of w unchanged; otherwise w is entirely in upper case if it is one of
+// the acronyms, and the rest is in lower case if it is not.
+func titleWord(w string, acronyms Acronyms) string {
+	upper := strings.ToUpper(w)
+	if acronyms[upper] {
+		return upper
+	}
+	if acronyms != nil {
+		w = strings.ToLower(w)
+	}
+	runes := []rune(w)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// word is a word of a name.
+type word struct {
+	text string
+	// joined reports whether the word follows the previous one without an
+	// explicit boundary, such as "world" in "hello2world".
+	joined bool
+}
+
+// splitWords : Splits the name s into words.
+// Words are separated by the characters '_', '-' and '.', by a lowercase letter
+// followed by an uppercase letter, and by the last capital of a run of capitals
+// followed by a lowercase letter, so that "HTTPServer" is split into "HTTP" and "Server".
+// Numbers form their own words; a number and a following lowercase word are joined.
+// The function returns nil if s does not start with a letter, does not end with a letter
+// or a number, or contains characters other than letters, numbers, '_', '-', and '.'.
+// For example:
+// splitWords("userID") returns "user", "ID".
+// splitWords("hello2world") returns "hello", "2", "world" (joined).
+func splitWords(s string) []word {
+	runes := []rune(s)
+	l := len(runes)
+	if l == 0 || !unicode.IsLetter(runes[0]) ||
+		(!unicode.IsLetter(runes[l-1]) && !unicode.IsNumber(runes[l-1])) {
+		return nil
+	}
+
+	var words []word
+	start := 0
+	joined := false
+	flush := func(end int) {
+		if end > start {
+			words = append(words, word{text: string(runes[start:end]), joined: joined})
+		}
+	}
+	for i := 0; i < l; i++ {
+		r := runes[i]
+		if !checkCompliance(r) {
+			return nil
+		}
+		if checkSpeChars(r) {
+			flush(i)
+			start, joined = i+1, false
 			continue
 		}
-		if checkSpeChars(char) && !isSeparator {
-			result.WriteRune('_')
-			isSeparator = true
+		if i == start {
 			continue
 		}
-		if unicode.IsLetter(char) {
-			if (!isSeparator && unicode.IsUpper(char)) || unicode.IsNumber(rune(input[i-1])) {
-				result.WriteRune('_')
-			}
-		} else if unicode.IsNumber(char) {
-			if !isSeparator {
-				result.WriteRune('_')
-			}
+		prev := runes[i-1]
+		switch {
+		case unicode.IsLower(prev) && unicode.IsUpper(r),
+			unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < l && unicode.IsLower(runes[i+1]):
+			flush(i)
+			start, joined = i, false
+		case unicode.IsLetter(prev) && unicode.IsNumber(r):
+			flush(i)
+			start, joined = i, true
+		case unicode.IsNumber(prev) && unicode.IsLetter(r):
+			flush(i)
+			start, joined = i, !unicode.IsUpper(r)
 		}
-		result.WriteRune(unicode.ToLower(char))
-		isSeparator = false
 	}
-
-	return result.String()
+	flush(l)
+	return words
 }
 
 // checkSpeChars : Check if the character is a special character.
@@ -1,6 +1,8 @@
 package querystring
 
 import (
+	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -185,3 +187,240 @@ func TestToSnakeCase(t *testing.T) {
 		}
 	}
 }
+
+func TestNamingStrategies(t *testing.T) {
+	tests := []struct {
+		n      Name
+		input  string
+		expect string
+	}{
+		{KebabCase, "HelloWorld", "hello-world"},
+		{KebabCase, "UserID", "user-id"},
+		{KebabCase, "hello2world", "hello-2-world"},
+		{KebabCase, "foo-bar-", ""},
+		{ScreamingSnakeCase, "HelloWorld", "HELLO_WORLD"},
+		{ScreamingSnakeCase, "HTTPServer", "HTTP_SERVER"},
+		{ScreamingSnakeCase, "hello.world", "HELLO_WORLD"},
+		{DotCase, "HelloWorld", "hello.world"},
+		{DotCase, "hello_world-hi", "hello.world.hi"},
+		{DotCase, "APIKeyV2", "api.key.v.2"},
+		{TrainCase, "hello_world", "Hello-World"},
+		{TrainCase, "UserID", "User-ID"},
+		{TrainCase, "contentType", "Content-Type"},
+		{FlatCase, "HelloWorld", "helloworld"},
+		{FlatCase, "user_id", "userid"},
+		{FlatCase, "1foo", ""},
+	}
+	for _, test := range tests {
+		actual := test.n.Convert(test.input)
+		if actual != test.expect {
+			t.Errorf("Failed to convert to %s case(input:%s, actual: %s, expect: %s)", test.n, test.input, actual, test.expect)
+		}
+	}
+
+	funcs := map[Name]func(string) string{
+		KebabCase:          ToKebabCase,
+		ScreamingSnakeCase: ToScreamingSnakeCase,
+		DotCase:            ToDotCase,
+		TrainCase:          ToTrainCase,
+		FlatCase:           ToFlatCase,
+	}
+	for _, test := range tests {
+		if actual := funcs[test.n](test.input); actual != test.expect {
+			t.Errorf("Failed to convert to %s case(input:%s, actual: %s, expect: %s)", test.n, test.input, actual, test.expect)
+		}
+	}
+}
+
+func TestAcronyms(t *testing.T) {
+	tests := []struct {
+		n        Name
+		input    string
+		acronyms Acronyms
+		expect   string
+	}{
+		{SnakeCase, "UserID", nil, "user_id"},
+		{SnakeCase, "HTTPServer", nil, "http_server"},
+		{SnakeCase, "APIKeyV2", nil, "api_key_v_2"},
+		{SnakeCase, "ServeHTTP", nil, "serve_http"},
+		{CamelCase, "UserID", DefaultAcronyms, "userID"},
+		{CamelCase, "UserID", nil, "userID"},
+		{CamelCase, "UserId", nil, "userId"},
+		{CamelCase, "UserId", DefaultAcronyms, "userID"},
+		{PascalCase, "ApiKey", nil, "ApiKey"},
+		{PascalCase, "ApiKey", DefaultAcronyms, "APIKey"},
+		{CamelCase, "user_id", DefaultAcronyms, "userID"},
+		{CamelCase, "HTTPServer", DefaultAcronyms, "httpServer"},
+		{CamelCase, "HTTPServerURL", NewAcronyms("url"), "httpServerURL"},
+		{PascalCase, "http_server_id", DefaultAcronyms, "HTTPServerID"},
+		{PascalCase, "http_server_id", nil, "HttpServerId"},
+		{PascalCase, "HTTPServer", NewAcronyms(), "HttpServer"},
+		{CamelCase, "UserID", NewAcronyms(), "userId"},
+		{TrainCase, "HTTPServer", NewAcronyms(), "Http-Server"},
+		{PascalCase, "http_server", NewAcronyms(), "HttpServer"},
+	}
+	for _, test := range tests {
+		actual := test.n.ConvertWith(test.input, test.acronyms)
+		if actual != test.expect {
+			t.Errorf("Failed to convert to %s case(input:%s, actual: %s, expect: %s)", test.n, test.input, actual, test.expect)
+		}
+	}
+}
+
+func TestConvertUnicode(t *testing.T) {
+	tests := []struct {
+		n      Name
+		input  string
+		expect string
+	}{
+		{SnakeCase, "ÜberName", "über_name"},
+		{SnakeCase, "名前", "名前"},
+		{CamelCase, "straße_größe", "straßeGröße"},
+		{PascalCase, "élan_vital", "ÉlanVital"},
+		{CamelCase, "ÉlanVital", "élanVital"},
+		{CamelCase, "élan vital", ""},
+	}
+	for _, test := range tests {
+		actual := test.n.Convert(test.input)
+		if actual != test.expect {
+			t.Errorf("Failed to convert to %s case(input:%s, actual: %s, expect: %s)", test.n, test.input, actual, test.expect)
+		}
+	}
+}
+
+func TestTagAcronyms(t *testing.T) {
+	type Input struct {
+		UserId int
+		ApiKey string
+		UserID int
+	}
+	tests := []struct {
+		opts   []Option
+		field  int
+		expect string
+	}{
+		{[]Option{WithUseName(CamelCase)}, 0, "userId"},
+		{[]Option{WithUseName(PascalCase)}, 1, "ApiKey"},
+		{[]Option{WithUseName(CamelCase), WithAcronyms(DefaultAcronyms)}, 0, "userID"},
+		{[]Option{WithUseName(PascalCase), WithAcronyms(DefaultAcronyms)}, 1, "APIKey"},
+		{[]Option{WithUseName(SnakeCase), WithAcronyms(DefaultAcronyms)}, 0, "user_id"},
+		{[]Option{WithUseName(CamelCase), WithAcronyms(NewAcronyms())}, 2, "userId"},
+		{[]Option{WithUseName(CamelCase)}, 2, "userID"},
+	}
+	for _, test := range tests {
+		field := reflect.TypeOf(Input{}).Field(test.field)
+		if name, _ := NewTag(test.opts...).Get(field); name != test.expect {
+			t.Errorf("expected %q, got %q", test.expect, name)
+		}
+	}
+}
+
+func TestNameConverter(t *testing.T) {
+	legacy := map[string]string{"UserID": "uid"}
+	converter := NameConverterFunc(func(name string) string {
+		if key, ok := legacy[name]; ok {
+			return key
+		}
+		return "x_" + ToSnakeCase(name)
+	})
+	type Input struct {
+		UserID    int
+		PageSize  int
+		SortOrder string `url:"sort"`
+	}
+	c := NewConverter(NewTag(WithNameConverter(converter), WithUseName(CamelCase)))
+	values, err := c.Values(Input{UserID: 7, PageSize: 20, SortOrder: "asc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "sort=asc&uid=7&x_page_size=20"
+	if actual := values.Encode(); actual != expect {
+		t.Errorf("expected %q, got %q", expect, actual)
+	}
+
+	field := reflect.TypeOf(Input{}).Field(1)
+	if name, _ := NewTag(WithNameConverter(KebabCase)).Get(field); name != "page-size" {
+		t.Errorf("expected %q, got %q", "page-size", name)
+	}
+}
+
+func TestTryConvert(t *testing.T) {
+	tests := []struct {
+		n      Name
+		input  string
+		expect string
+		err    bool
+	}{
+		{SnakeCase, "HelloWorld", "hello_world", false},
+		{CamelCase, "Type_", "", true},
+		{KebabCase, "1foo", "", true},
+		{"", "Type_", "Type_", false},
+		{"kebob", "HelloWorld", "", true},
+	}
+	for _, test := range tests {
+		actual, err := test.n.TryConvert(test.input)
+		if actual != test.expect || (err != nil) != test.err {
+			t.Errorf("Failed to convert to %s case(input:%s, actual: %s, %v, expect: %s)", test.n, test.input, actual, err, test.expect)
+		}
+	}
+}
+
+func TestNormalizeKeys(t *testing.T) {
+	type Filter struct {
+		CreatedAt string `url:"createdAt"`
+		OwnerID   int
+	}
+	type Input struct {
+		PageSize int               `url:"page-size"`
+		Filter   Filter            `url:"Filter"`
+		Labels   map[string]string `url:"labels"`
+	}
+	in := Input{
+		PageSize: 10,
+		Filter:   Filter{CreatedAt: "today", OwnerID: 3},
+		Labels:   map[string]string{"teamName": "core"},
+	}
+
+	c := NewConverter(NewTag(WithUseName(CamelCase), WithNormalizeKeys(true)), WithStyle(StylePHP))
+	values, err := c.Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := url.Values{
+		"pageSize":          {"10"},
+		"filter[createdAt]": {"today"},
+		"filter[ownerID]":   {"3"},
+		"labels[teamName]":  {"core"},
+	}
+	if !reflect.DeepEqual(values, expect) {
+		t.Errorf("expected %v, got %v", expect, values)
+	}
+
+	c = NewConverter(NewTag(WithUseName(KebabCase), WithNormalizeKeys(true)), WithStyle(StyleQS))
+	values, err = c.Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect = url.Values{
+		"page-size":          {"10"},
+		"filter[created-at]": {"today"},
+		"filter[owner-id]":   {"3"},
+		"labels[team-name]":  {"core"},
+	}
+	if !reflect.DeepEqual(values, expect) {
+		t.Errorf("expected %v, got %v", expect, values)
+	}
+
+	var out Input
+	if err := c.Decode(values, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.PageSize != 10 || out.Filter != in.Filter {
+		t.Errorf("unexpected decoded value %+v", out)
+	}
+
+	in.Labels = map[string]string{"team name": "core"}
+	if _, err := c.Values(in); err == nil {
+		t.Error("expected an error for a map key that cannot be converted")
+	}
+}
@@ -0,0 +1,220 @@
+package querystring
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The OpenAPI 3 styles for query parameters, selected with the tag option style=<name>.
+// The tag option explode=true|false controls whether arrays and objects are
+// exploded into separate parameters.
+const (
+	ParamStyleForm           = "form"
+	ParamStyleSpaceDelimited = "spaceDelimited"
+	ParamStylePipeDelimited  = "pipeDelimited"
+	ParamStyleDeepObject     = "deepObject"
+)
+
+// paramStyle is an OpenAPI serialization style with its explode setting.
+type paramStyle struct {
+	name    string
+	explode bool
+}
+
+// parseParamStyle reads the style and explode options from the tag options.
+// The second return value reports whether the tag options select an OpenAPI style.
+func parseParamStyle(opts TagOptions) (paramStyle, bool, error) {
+	name, hasStyle := opts.Value("style")
+	explode, hasExplode := opts.Value("explode")
+	if !hasStyle && !hasExplode {
+		return paramStyle{}, false, nil
+	}
+	if !hasStyle {
+		name = ParamStyleForm
+	}
+	ps := paramStyle{name: name}
+	switch name {
+	case ParamStyleForm, ParamStyleDeepObject:
+		ps.explode = true
+	case ParamStyleSpaceDelimited, ParamStylePipeDelimited:
+	default:
+		return ps, false, fmt.Errorf("unsupported style %q", name)
+	}
+	if hasExplode {
+		switch explode {
+		case "true":
+			ps.explode = true
+		case "false":
+			ps.explode = false
+		default:
+			return ps, false, fmt.Errorf("invalid explode value %q", explode)
+		}
+	}
+	if ps.name == ParamStyleDeepObject && !ps.explode {
+		return ps, false, fmt.Errorf("style %q requires explode=true", ps.name)
+	}
+	return ps, true, nil
+}
+
+// delimiter returns the separator used when the style is not exploded.
+func (ps paramStyle) delimiter() string {
+	switch ps.name {
+	case ParamStyleSpaceDelimited:
+		return " "
+	case ParamStylePipeDelimited:
+		return "|"
+	default:
+		return ","
+	}
+}
+
+// encodeParam adds sv to values under the key name, serialized according to
+// the OpenAPI style ps.
+func (c *Converter) encodeParam(values url.Values, name string, sv reflect.Value, ps paramStyle) error {
+	if sv.Kind() == reflect.Struct && sv.Type().Implements(optionalType) {
+		value, present, null := sv.Interface().(optional).optionalGet()
+		if !present {
+			return nil
+		}
+		if null {
+			if !c.style.SkipNull {
+				values.Add(name, c.style.Null)
+			}
+			return nil
+		}
+		sv = value
+	}
+	for sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface {
+		if sv.IsNil() {
+			if !c.style.SkipNull {
+				values.Add(name, c.style.Null)
+			}
+			return nil
+		}
+		if sv.Type().Implements(encoderType) {
+			break
+		}
+		sv = sv.Elem()
+	}
+
+	if s, ok := c.formatValue(sv); ok {
+		if ps.name != ParamStyleForm {
+			return fmt.Errorf("style %q does not support primitive value for %q", ps.name, name)
+		}
+		values.Add(name, s)
+		return nil
+	}
+
+	var items []string
+	var object bool
+	switch {
+	case sv.Type().Implements(encoderType):
+		encoded, err := sv.Interface().(Encoder).Encode()
+		if err != nil {
+			return err
+		}
+		items = encoded
+	case sv.Kind() == reflect.Slice || sv.Kind() == reflect.Array:
+		for i := 0; i < sv.Len(); i++ {
+			s, ok := c.formatValue(sv.Index(i))
+			if !ok {
+				return fmt.Errorf("style %q does not support nested value for %q", ps.name, name)
+			}
+			items = append(items, s)
+		}
+	case sv.Kind() == reflect.Struct || sv.Kind() == reflect.Map:
+		pairs, err := c.objectPairs(sv)
+		if err != nil {
+			return err
+		}
+		items = pairs
+		object = true
+	default:
+		return nil
+	}
+
+	if ps.name == ParamStyleDeepObject {
+		if !object {
+			return fmt.Errorf("style %q does not support array value for %q", ps.name, name)
+		}
+		for i := 0; i < len(items); i += 2 {
+			values.Add(name+"["+items[i]+"]", items[i+1])
+		}
+		return nil
+	}
+	if !ps.explode {
+		values.Add(name, strings.Join(items, ps.delimiter()))
+		return nil
+	}
+	if object {
+		for i := 0; i < len(items); i += 2 {
+			values.Add(items[i], items[i+1])
+		}
+		return nil
+	}
+	for _, item := range items {
+		values.Add(name, item)
+	}
+	return nil
+}
+
+// objectPairs returns the properties of the struct or map sv as a flat list
+// of alternating names and values.
+func (c *Converter) objectPairs(sv reflect.Value) ([]string, error) {
+	var pairs []string
+	add := func(name string, v reflect.Value) error {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+		s, ok := c.formatValue(v)
+		if !ok {
+			return fmt.Errorf("nested value for property %q is not supported", name)
+		}
+		pairs = append(pairs, name, s)
+		return nil
+	}
+
+	if sv.Kind() == reflect.Map {
+		if sv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key must be a string")
+		}
+		keys := sv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		for _, k := range keys {
+			key, err := c.mapKey(k.String())
+			if err != nil {
+				return nil, fmt.Errorf("map key %q: %w", k.String(), err)
+			}
+			if err := add(key, sv.MapIndex(k)); err != nil {
+				return nil, err
+			}
+		}
+		return pairs, nil
+	}
+
+	p, err := c.plan(sv.Type())
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range p.fields {
+		fv, ok := fieldValue(sv, f.index, false)
+		if !ok || f.opts.Contains("remain") || f.until != "" {
+			continue
+		}
+		if f.opts.Contains("omitempty") && isEmptyValue(fv) {
+			continue
+		}
+		if err := add(f.name, fv); err != nil {
+			return nil, err
+		}
+	}
+	return pairs, nil
+}
@@ -0,0 +1,106 @@
+package querystring
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type openAPIColor struct {
+	R int `url:"R"`
+	G int `url:"G"`
+	B int `url:"B"`
+}
+
+// TestParamStyle uses the examples of the style table of the OpenAPI 3.1
+// specification, with color set to "blue", ["blue","black","brown"] and
+// {"R":100,"G":200,"B":150}.
+func TestParamStyle(t *testing.T) {
+	colors := []string{"blue", "black", "brown"}
+	rgb := openAPIColor{R: 100, G: 200, B: 150}
+
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected string
+	}{
+		{"form primitive", struct {
+			C string `url:"color,style=form,explode=false"`
+		}{"blue"}, "color=blue"},
+		{"form explode primitive", struct {
+			C string `url:"color,style=form,explode=true"`
+		}{"blue"}, "color=blue"},
+		{"form array", struct {
+			C []string `url:"color,style=form,explode=false"`
+		}{colors}, "color=blue,black,brown"},
+		{"form explode array", struct {
+			C []string `url:"color,style=form,explode=true"`
+		}{colors}, "color=blue&color=black&color=brown"},
+		{"spaceDelimited array", struct {
+			C []string `url:"color,style=spaceDelimited,explode=false"`
+		}{colors}, "color=blue%20black%20brown"},
+		{"pipeDelimited array", struct {
+			C []string `url:"color,style=pipeDelimited,explode=false"`
+		}{colors}, "color=blue|black|brown"},
+		{"form object", struct {
+			C openAPIColor `url:"color,style=form,explode=false"`
+		}{rgb}, "color=R,100,G,200,B,150"},
+		{"form explode object", struct {
+			C openAPIColor `url:"color,style=form,explode=true"`
+		}{rgb}, "R=100&G=200&B=150"},
+		{"spaceDelimited object", struct {
+			C openAPIColor `url:"color,style=spaceDelimited,explode=false"`
+		}{rgb}, "color=R%20100%20G%20200%20B%20150"},
+		{"pipeDelimited object", struct {
+			C openAPIColor `url:"color,style=pipeDelimited,explode=false"`
+		}{rgb}, "color=R|100|G|200|B|150"},
+		{"deepObject object", struct {
+			C openAPIColor `url:"color,style=deepObject,explode=true"`
+		}{rgb}, "color[R]=100&color[G]=200&color[B]=150"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := Values(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := url.ParseQuery(tt.expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(values, expected) {
+				t.Errorf("expected %v, got %v", expected, values)
+			}
+		})
+	}
+}
+
+func TestParamStyleErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{"unknown style", struct {
+			A []string `url:"a,style=matrix"`
+		}{A: []string{"x"}}},
+		{"invalid explode", struct {
+			A []string `url:"a,explode=yes"`
+		}{A: []string{"x"}}},
+		{"deepObject without explode", struct {
+			A openAPIColor `url:"a,style=deepObject,explode=false"`
+		}{}},
+		{"deepObject array", struct {
+			A []string `url:"a,style=deepObject"`
+		}{A: []string{"x"}}},
+		{"pipeDelimited primitive", struct {
+			A string `url:"a,style=pipeDelimited"`
+		}{A: "x"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Values(tt.input); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package querystring
+
+import "reflect"
+
+var (
+	optionalType       = reflect.TypeOf((*optional)(nil)).Elem()
+	optionalSetterType = reflect.TypeOf((*optionalSetter)(nil)).Elem()
+)
+
+// optional is implemented by Optional and Nullable and gives the Converter
+// access to the wrapped value when encoding.
+type optional interface {
+	// optionalGet returns the wrapped value, whether it is present and whether it is null.
+	optionalGet() (reflect.Value, bool, bool)
+}
+
+// optionalSetter is implemented by pointers to Optional and Nullable and gives
+// the Converter access to the wrapped value when decoding.
+type optionalSetter interface {
+	// optionalSet returns the settable wrapped value and the presence flag.
+	// The null flag is nil if the type cannot be null.
+	optionalSet() (reflect.Value, *bool, *bool)
+}
+
+// Optional is a value that may be absent.
+// An absent Optional is omitted when encoding. When decoding, Present reports
+// whether the key was sent at all, which distinguishes an absent parameter from a zero value.
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// Some returns a present Optional holding value.
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Value: value, Present: true}
+}
+
+// Get returns the value and whether it is present.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Present
+}
+
+// IsZero reports whether the value is absent.
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+func (o Optional[T]) optionalGet() (reflect.Value, bool, bool) {
+	return reflect.ValueOf(&o.Value).Elem(), o.Present, false
+}
+
+func (o *Optional[T]) optionalSet() (reflect.Value, *bool, *bool) {
+	return reflect.ValueOf(&o.Value).Elem(), &o.Present, nil
+}
+
+// Nullable is a value that may be absent, explicitly null, or set.
+// An absent Nullable is omitted when encoding and a null Nullable is encoded
+// as the Null value of the Style. When decoding, a key holding only the
+// Null value of the Style decodes as null.
+// Styles that skip nulls, such as StyleDefault, cannot represent null: a null
+// Nullable is omitted like a nil pointer, and the Null value decodes as a value.
+// Styles whose Null value is "", such as StyleQS, decode an empty value as null.
+type Nullable[T any] struct {
+	Value   T
+	Present bool
+	Null    bool
+}
+
+// NullOf returns a present, null Nullable.
+func NullOf[T any]() Nullable[T] {
+	return Nullable[T]{Present: true, Null: true}
+}
+
+// NotNull returns a present Nullable holding value.
+func NotNull[T any](value T) Nullable[T] {
+	return Nullable[T]{Value: value, Present: true}
+}
+
+// Get returns the value and whether it is present and not null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.Value, n.Present && !n.Null
+}
+
+// IsZero reports whether the value is absent.
+func (n Nullable[T]) IsZero() bool {
+	return !n.Present
+}
+
+func (n Nullable[T]) optionalGet() (reflect.Value, bool, bool) {
+	return reflect.ValueOf(&n.Value).Elem(), n.Present, n.Null
+}
+
+func (n *Nullable[T]) optionalSet() (reflect.Value, *bool, *bool) {
+	return reflect.ValueOf(&n.Value).Elem(), &n.Present, &n.Null
+}
+
+// optionalElem returns the type wrapped by the Optional or Nullable type typ.
+// The second return value is false if typ is neither.
+func optionalElem(typ reflect.Type) (reflect.Type, bool) {
+	if typ.Kind() != reflect.Struct || !reflect.PointerTo(typ).Implements(optionalSetterType) {
+		return nil, false
+	}
+	return typ.Field(0).Type, true
+}
@@ -0,0 +1,138 @@
+package querystring
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestDecodePresence(t *testing.T) {
+	type Filter struct {
+		Name string `url:"name"`
+		Min  int    `url:"min"`
+	}
+	type Input struct {
+		Limit  int              `url:"limit"`
+		Offset int              `url:"offset"`
+		Query  Optional[string] `url:"q"`
+		Page   Optional[int]    `url:"page"`
+		Tags   Optional[[]string]
+		Filter Filter `url:"filter"`
+	}
+	values := url.Values{
+		"limit":        {"0"},
+		"q":            {""},
+		"tags[0]":      {"a"},
+		"tags[1]":      {"b"},
+		"filter[name]": {"x"},
+	}
+	var in Input
+	presence, err := NewConverter(NewTag(), WithStyle(StyleQS)).DecodePresence(values, &in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"Limit", "Query", "Tags", "Filter", "Filter.Name"} {
+		if !presence.Has(path) {
+			t.Errorf("expected %s to be present", path)
+		}
+	}
+	for _, path := range []string{"Offset", "Page", "Filter.Min"} {
+		if presence.Has(path) {
+			t.Errorf("expected %s to be absent", path)
+		}
+	}
+
+	if q, ok := in.Query.Get(); !ok || q != "" {
+		t.Errorf("expected present empty query, got %q, %t", q, ok)
+	}
+	if _, ok := in.Page.Get(); ok {
+		t.Error("expected page to be absent")
+	}
+	if tags, ok := in.Tags.Get(); !ok || len(tags) != 2 {
+		t.Errorf("expected two tags, got %v, %t", tags, ok)
+	}
+	if in.Filter.Name != "x" {
+		t.Errorf("expected filter name %q, got %q", "x", in.Filter.Name)
+	}
+}
+
+func TestOptionalValues(t *testing.T) {
+	type Input struct {
+		Limit  Optional[int]      `url:"limit"`
+		Offset Optional[int]      `url:"offset"`
+		Parent Nullable[string]   `url:"parent"`
+		Owner  Nullable[string]   `url:"owner"`
+		Label  Nullable[string]   `url:"label"`
+		Tags   Optional[[]string] `url:"tags"`
+		Page   *Optional[int]     `url:"page"`
+		Extra  Optional[string]   `url:"extra,omitempty"`
+	}
+	style := StyleDefault
+	style.Null = "null"
+	style.SkipNull = false
+	con := NewConverter(NewTag(), WithStyle(style))
+	in := Input{
+		Limit:  Some(0),
+		Parent: NullOf[string](),
+		Owner:  NotNull("fred"),
+		Tags:   Some([]string{"a", "b"}),
+	}
+	values, err := con.Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{
+		"limit":  {"0"},
+		"parent": {"null"},
+		"owner":  {"fred"},
+		"tags":   {"a", "b"},
+		"page":   {"null"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	var out Input
+	if err := con.Decode(values, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+}
+
+func TestNullableSkipNull(t *testing.T) {
+	type Input struct {
+		Name   Nullable[string] `url:"name"`
+		Parent Nullable[string] `url:"parent"`
+	}
+	in := Input{Name: NotNull(""), Parent: NullOf[string]()}
+	values, err := Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{"name": {""}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+	var out Input
+	if err := Decode(values, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != NotNull("") || out.Parent.Present {
+		t.Errorf("expected a present empty name and an absent parent, got %+v", out)
+	}
+
+	con := NewConverter(NewTag(), WithStyle(StyleQS))
+	values, err = con.Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out = Input{}
+	if err := con.Decode(values, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !out.Name.Null || !out.Parent.Null {
+		t.Errorf("expected StyleQS to decode the empty Null value as null, got %+v", out)
+	}
+}
@@ -1,7 +1,13 @@
 package querystring
 
+import "time"
+
 type option struct {
 	useName   Name
+	converter NameConverter
+	fallback  bool
+	normalize bool
+	acronyms  Acronyms
 	skipField string
 	tag       string
 }
@@ -14,6 +20,44 @@ func WithUseName(useName Name) Option {
 	}
 }
 
+// WithNameConverter sets the converter that names the fields without a tag.
+// It takes precedence over WithUseName and WithAcronyms.
+func WithNameConverter(converter NameConverter) Option {
+	return func(o *option) {
+		o.converter = converter
+	}
+}
+
+// WithNameFallback sets whether a field whose name cannot be converted is keyed
+// by its Go field name. Without the fallback, such a field makes the Converter
+// return an error naming the field.
+func WithNameFallback(fallback bool) Option {
+	return func(o *option) {
+		o.fallback = fallback
+	}
+}
+
+// WithNormalizeKeys sets whether every key is converted with the configured Name
+// or NameConverter, including the names of explicit tags and the keys of nested
+// maps, so that nested keys are built from converted segments joined by the
+// nesting style of the Converter.
+func WithNormalizeKeys(normalize bool) Option {
+	return func(o *option) {
+		o.normalize = normalize
+	}
+}
+
+// WithAcronyms sets the words that CamelCase and PascalCase render in upper case.
+// The default, nil, keeps the case of the words of field names: "UserId" is
+// rendered "userId" and "UserID" is rendered "userID" in camel case.
+// A non-nil set renders the other words in lower case: with NewAcronyms(), both
+// are rendered "userId", and with DefaultAcronyms, both are rendered "userID".
+func WithAcronyms(acronyms Acronyms) Option {
+	return func(o *option) {
+		o.acronyms = acronyms
+	}
+}
+
 func WithSkipField(skipField string) Option {
 	return func(o *option) {
 		o.skipField = skipField
@@ -34,3 +78,169 @@ func defaultOption() *option {
 	}
 	return opt
 }
+
+type converterOption struct {
+	style         Style
+	unknownKeys   UnknownKeys
+	unknownReport func(keys []string)
+	dominance     bool
+	groups        []string
+	version       string
+	deprecated    func(key, until string)
+}
+
+type ConverterOption func(*converterOption)
+
+// WithStyle sets the style used to render nested values, arrays, booleans and nil values.
+func WithStyle(style Style) ConverterOption {
+	return func(o *converterOption) {
+		o.style = style
+	}
+}
+
+// WithUnknownKeys sets what decoding does with keys that match no field.
+func WithUnknownKeys(policy UnknownKeys) ConverterOption {
+	return func(o *converterOption) {
+		o.unknownKeys = policy
+	}
+}
+
+// WithUnknownKeysHandler reports the keys that match no field to fn when decoding.
+// It sets the ReportUnknownKeys policy.
+func WithUnknownKeysHandler(fn func(keys []string)) ConverterOption {
+	return func(o *converterOption) {
+		o.unknownKeys = ReportUnknownKeys
+		o.unknownReport = fn
+	}
+}
+
+// WithDominance resolves fields sharing a key with the rules of encoding/json
+// instead of failing: the shallowest field wins, then the only tagged one.
+func WithDominance(dominance bool) ConverterOption {
+	return func(o *converterOption) {
+		o.dominance = dominance
+	}
+}
+
+// WithGroups sets the active groups. Fields with the tag option groups=<name>|<name>
+// are encoded and decoded only if one of their groups is active; fields without
+// groups are always encoded and decoded.
+func WithGroups(groups ...string) ConverterOption {
+	return func(o *converterOption) {
+		o.groups = append(o.groups, groups...)
+	}
+}
+
+// WithVersion sets the target API version, such as "v3".
+// Fields with the tag option since=<version> are encoded and decoded only from
+// that version on. Fields with the tag option until=<version> are deprecated
+// from that version on: they are no longer encoded, and decoding them calls
+// the handler set with WithDeprecationHandler.
+func WithVersion(version string) ConverterOption {
+	return func(o *converterOption) {
+		o.version = version
+	}
+}
+
+// WithDeprecationHandler sets the function called when decoding finds a key of
+// a field deprecated in the target version, with the version it was deprecated in.
+func WithDeprecationHandler(fn func(key, until string)) ConverterOption {
+	return func(o *converterOption) {
+		o.deprecated = fn
+	}
+}
+
+func defaultConverterOption() *converterOption {
+	opt := &converterOption{
+		style: StyleDefault,
+	}
+	return opt
+}
+
+type callOption struct {
+	only   []string
+	except []string
+}
+
+// CallOption configures a single call of Values, Decode or DecodePresence.
+type CallOption func(*callOption)
+
+// Only restricts the call to the fields of the struct, keyed by Go field path,
+// such as "Base.ID" for a field promoted from the embedded struct Base, or by query name.
+func Only(fields ...string) CallOption {
+	return func(o *callOption) {
+		o.only = append(o.only, fields...)
+	}
+}
+
+// Except excludes the fields of the struct from the call, keyed by Go field path or by query name.
+func Except(fields ...string) CallOption {
+	return func(o *callOption) {
+		o.except = append(o.except, fields...)
+	}
+}
+
+type compareOption struct {
+	ignoreOrder bool
+}
+
+// CompareOption configures CompareValues.
+type CompareOption func(*compareOption)
+
+// WithIgnoreValueOrder sets whether the values of a key are compared regardless of their order.
+func WithIgnoreValueOrder(ignore bool) CompareOption {
+	return func(o *compareOption) {
+		o.ignoreOrder = ignore
+	}
+}
+
+func defaultCompareOption() *compareOption {
+	return &compareOption{}
+}
+
+type signerOption struct {
+	now  func() time.Time
+	keys map[string][]byte
+}
+
+type SignerOption func(*signerOption)
+
+// WithClock sets the function used to read the current time.
+func WithClock(now func() time.Time) SignerOption {
+	return func(o *signerOption) {
+		o.now = now
+	}
+}
+
+// WithVerifyKey adds a key that is accepted when verifying signatures,
+// such as a key that has been rotated out for signing.
+func WithVerifyKey(keyID string, key []byte) SignerOption {
+	return func(o *signerOption) {
+		o.keys[keyID] = key
+	}
+}
+
+func defaultSignerOption() *signerOption {
+	opt := &signerOption{
+		now:  time.Now,
+		keys: make(map[string][]byte),
+	}
+	return opt
+}
+
+type cursorOption struct {
+	key []byte
+}
+
+type CursorOption func(*cursorOption)
+
+// WithCursorKey signs cursors with HMAC-SHA256 so that clients cannot forge them.
+func WithCursorKey(key []byte) CursorOption {
+	return func(o *cursorOption) {
+		o.key = key
+	}
+}
+
+func defaultCursorOption() *cursorOption {
+	return &cursorOption{}
+}
@@ -0,0 +1,164 @@
+package querystring
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// BuildURL substitutes the path parameters of pattern with the fields of v
+// that have the "path" tag option, and appends the remaining fields as the query string.
+// Placeholders are written as {name} or :name, where :name must start a path segment.
+// Values are escaped with url.PathEscape. The query string is inserted before
+// the fragment of pattern, if any.
+// An error is returned if a placeholder has no matching field, a path field has
+// no matching placeholder, or a path field is empty.
+func (c *Converter) BuildURL(pattern string, v interface{}) (string, error) {
+	params, err := c.pathParams(v)
+	if err != nil {
+		return "", err
+	}
+	path, err := substitutePath(pattern, params)
+	if err != nil {
+		return "", err
+	}
+	values, err := c.Values(v)
+	if err != nil {
+		return "", err
+	}
+	if len(values) == 0 {
+		return path, nil
+	}
+	// Substituted values are escaped, so the first '#' starts the fragment of the pattern.
+	path, fragment, hasFragment := strings.Cut(path, "#")
+	if strings.Contains(path, "?") {
+		path += "&" + values.Encode()
+	} else {
+		path += "?" + values.Encode()
+	}
+	if hasFragment {
+		path += "#" + fragment
+	}
+	return path, nil
+}
+
+// pathParams collects the values of the fields of v with the "path" tag option.
+func (c *Converter) pathParams(v interface{}) (map[string]string, error) {
+	params := make(map[string]string)
+	if v == nil {
+		return params, nil
+	}
+	vf := reflect.ValueOf(v)
+	if vf.Kind() == reflect.Ptr {
+		if vf.IsNil() {
+			return params, nil
+		}
+		vf = vf.Elem()
+	}
+	if vf.Kind() != reflect.Struct {
+		return params, nil
+	}
+	p, err := c.plan(vf.Type())
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range p.fields {
+		if !f.opts.Contains("path") {
+			continue
+		}
+		var value string
+		if sv, ok := fieldValue(vf, f.index, false); ok {
+			value, err = c.pathValue(sv)
+			if err != nil {
+				return nil, fmt.Errorf("path parameter %q: %w", f.name, err)
+			}
+		}
+		if value == "" {
+			return nil, fmt.Errorf("path parameter %q is empty", f.name)
+		}
+		params[f.name] = value
+	}
+	return params, nil
+}
+
+// pathValue returns the string form of a path parameter.
+func (c *Converter) pathValue(sv reflect.Value) (string, error) {
+	for sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface {
+		if sv.IsNil() {
+			return "", nil
+		}
+		if sv.Type().Implements(encoderType) {
+			break
+		}
+		sv = sv.Elem()
+	}
+	if sv.Type().Implements(encoderType) {
+		encoded, err := sv.Interface().(Encoder).Encode()
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(encoded, ","), nil
+	}
+	if s, ok := c.formatValue(sv); ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("unsupported type %s", sv.Type())
+}
+
+// substitutePath replaces the {name} and :name placeholders of pattern with
+// the escaped values of params. Every placeholder and every parameter must be used.
+func substitutePath(pattern string, params map[string]string) (string, error) {
+	used := make(map[string]bool, len(params))
+	var builder strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		var name string
+		switch {
+		case pattern[i] == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unclosed placeholder in %q", pattern)
+			}
+			name = pattern[i+1 : i+end]
+			i += end
+		case pattern[i] == ':' && i > 0 && pattern[i-1] == '/' && i+1 < len(pattern) && isPathNameStart(pattern[i+1]):
+			end := i + 1
+			for end < len(pattern) && isPathNameChar(pattern[end]) {
+				end++
+			}
+			name = pattern[i+1 : end]
+			i = end - 1
+		default:
+			builder.WriteByte(pattern[i])
+			continue
+		}
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("no path parameter for placeholder %q", name)
+		}
+		used[name] = true
+		builder.WriteString(url.PathEscape(value))
+	}
+	for name := range params {
+		if !used[name] {
+			return "", fmt.Errorf("no placeholder for path parameter %q", name)
+		}
+	}
+	return builder.String(), nil
+}
+
+func isPathNameStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isPathNameChar(c byte) bool {
+	return isPathNameStart(c) || c >= '0' && c <= '9'
+}
+
+// BuildURL substitutes the path parameters of pattern with the fields of v
+// and appends the remaining fields as the query string.
+// The fields must have a tag with the key "url".
+func BuildURL(pattern string, v interface{}) (string, error) {
+	converter := NewConverter(NewTag())
+	return converter.BuildURL(pattern, v)
+}
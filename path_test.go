@@ -0,0 +1,56 @@
+package querystring
+
+import "testing"
+
+func TestBuildURL(t *testing.T) {
+	type Input struct {
+		Owner string `url:"owner,path"`
+		ID    int    `url:"id,path"`
+		Sort  string `url:"sort,omitempty"`
+		Page  int    `url:"page"`
+	}
+	tests := []struct {
+		pattern  string
+		input    Input
+		expected string
+	}{
+		{"/users/{owner}/repos/{id}", Input{Owner: "fred", ID: 7, Page: 2}, "/users/fred/repos/7?page=2"},
+		{"/users/:owner/repos/:id", Input{Owner: "fred", ID: 7, Sort: "name"}, "/users/fred/repos/7?page=0&sort=name"},
+		{"http://example.com:8080/users/:owner/repos/{id}", Input{Owner: "a/b c", ID: 7}, "http://example.com:8080/users/a%2Fb%20c/repos/7?page=0"},
+		{"/users/{owner}/repos/{id}?fixed=yes", Input{Owner: "fred", ID: 7}, "/users/fred/repos/7?fixed=yes&page=0"},
+		{"http://h/u/:owner/{id}#frag", Input{Owner: "a#b", ID: 5, Sort: "a"}, "http://h/u/a%23b/5?page=0&sort=a#frag"},
+		{"/u/{owner}/{id}?fixed=yes#top", Input{Owner: "fred", ID: 5}, "/u/fred/5?fixed=yes&page=0#top"},
+	}
+	for _, tt := range tests {
+		got, err := BuildURL(tt.pattern, tt.input)
+		if err != nil {
+			t.Errorf("%s: %v", tt.pattern, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.pattern, tt.expected, got)
+		}
+	}
+}
+
+func TestBuildURLErrors(t *testing.T) {
+	type Input struct {
+		Owner string `url:"owner,path"`
+		ID    int    `url:"id,path"`
+	}
+	tests := []struct {
+		name    string
+		pattern string
+		input   Input
+	}{
+		{"unmatched placeholder", "/users/{owner}/repos/{id}/{branch}", Input{Owner: "fred", ID: 7}},
+		{"unused path field", "/users/{owner}", Input{Owner: "fred", ID: 7}},
+		{"empty path field", "/users/{owner}/repos/{id}", Input{ID: 7}},
+		{"unclosed placeholder", "/users/{owner/repos/{id}", Input{Owner: "fred", ID: 7}},
+	}
+	for _, tt := range tests {
+		if _, err := BuildURL(tt.pattern, tt.input); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
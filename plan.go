@@ -0,0 +1,259 @@
+package querystring
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// field is a struct field as seen by the Converter.
+type field struct {
+	// name is the key of the field.
+	name string
+	// path is the Go field path, such as "Base.ID" for a field promoted from
+	// the embedded struct Base.
+	path string
+	// index is the index sequence for reflect.Value.FieldByIndex.
+	index  []int
+	typ    reflect.Type
+	opts   TagOptions
+	tagged bool
+	// until is the version the field is deprecated in, if it is deprecated
+	// in the target version of the Converter.
+	until string
+}
+
+// plan is the list of fields of a struct type, in field order.
+type plan struct {
+	fields []field
+}
+
+// explicitTag is implemented by Tags that can tell whether a field has an explicit tag.
+// Untagged embedded structs are flattened only for such Tags.
+type explicitTag interface {
+	Explicit(field reflect.StructField) bool
+}
+
+// lookupTag is implemented by Tags that can tell why a field has no key.
+type lookupTag interface {
+	Lookup(field reflect.StructField) (string, bool, error)
+}
+
+// keyNormalizer is implemented by Tags that convert the keys of nested maps.
+type keyNormalizer interface {
+	NormalizeKey(key string) (string, error)
+}
+
+// mapKey returns the key of a map entry, normalized if the Tag normalizes keys.
+func (c *Converter) mapKey(key string) (string, error) {
+	if kn, ok := c.tag.(keyNormalizer); ok {
+		return kn.NormalizeKey(key)
+	}
+	return key, nil
+}
+
+// plan returns the cached field plan of the struct type typ.
+func (c *Converter) plan(typ reflect.Type) (*plan, error) {
+	if p, ok := c.plans.Load(typ); ok {
+		return p.(*plan), nil
+	}
+	p, err := c.buildPlan(typ)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := c.plans.LoadOrStore(typ, p)
+	return actual.(*plan), nil
+}
+
+func (c *Converter) buildPlan(typ reflect.Type) (*plan, error) {
+	var fields []field
+	if err := c.collectFields(typ, nil, "", map[reflect.Type]bool{typ: true}, &fields); err != nil {
+		return nil, fmt.Errorf("%w of %s", err, typ)
+	}
+
+	// Fields outside the active groups or the target version are dropped before
+	// looking for duplicates, so that such fields can share a key.
+	visible := fields[:0]
+	for _, f := range fields {
+		if !c.inGroups(f.opts) {
+			continue
+		}
+		exists, until, err := c.checkVersion(f.opts)
+		if err != nil {
+			return nil, fmt.Errorf("field %s of %s: %w", f.path, typ, err)
+		}
+		if exists {
+			f.until = until
+			visible = append(visible, f)
+		}
+	}
+	fields = visible
+
+	byName := make(map[string][]int)
+	for i, f := range fields {
+		if f.opts.Contains("remain") {
+			continue
+		}
+		byName[f.name] = append(byName[f.name], i)
+	}
+	drop := make(map[int]bool)
+	for name, group := range byName {
+		if len(group) == 1 {
+			continue
+		}
+		if !c.dominance {
+			return nil, fmt.Errorf("duplicate key %q for fields %s of %s", name, fieldPaths(fields, group), typ)
+		}
+		winner, ok := dominantField(fields, group)
+		if !ok {
+			return nil, fmt.Errorf("ambiguous key %q for fields %s of %s", name, fieldPaths(fields, group), typ)
+		}
+		for _, i := range group {
+			if i != winner {
+				drop[i] = true
+			}
+		}
+	}
+
+	p := &plan{}
+	for i, f := range fields {
+		if !drop[i] {
+			p.fields = append(p.fields, f)
+		}
+	}
+	return p, nil
+}
+
+// collectFields appends the fields of typ to fields, flattening untagged embedded structs.
+// An error is returned for a field that cannot be named.
+func (c *Converter) collectFields(typ reflect.Type, index []int, path string, visited map[reflect.Type]bool, fields *[]field) error {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous { // unexported
+			continue
+		}
+		var tag string
+		var ok bool
+		var tagErr error
+		if lt, isLookup := c.tag.(lookupTag); isLookup {
+			tag, ok, tagErr = lt.Lookup(sf)
+		} else {
+			tag, ok = c.tag.Get(sf)
+		}
+		if !ok {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+		tagged := true
+		if et, ok := c.tag.(explicitTag); ok {
+			tagged = et.Explicit(sf)
+		}
+
+		if sf.Anonymous {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if !tagged && flattenable(ft) {
+				if !visited[ft] {
+					visited[ft] = true
+					err := c.collectFields(ft, fieldIndex, fieldPath, visited, fields)
+					delete(visited, ft)
+					if err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if sf.PkgPath != "" { // unexported and not flattened
+				continue
+			}
+		}
+
+		if tagErr != nil {
+			return fmt.Errorf("field %s: %w", fieldPath, tagErr)
+		}
+		name, opts := c.tag.ParseTag(tag)
+		if name == "" && !opts.Contains("remain") {
+			return fmt.Errorf("field %s has an empty key", fieldPath)
+		}
+		*fields = append(*fields, field{
+			name:   name,
+			path:   fieldPath,
+			index:  fieldIndex,
+			typ:    sf.Type,
+			opts:   opts,
+			tagged: tagged,
+		})
+	}
+	return nil
+}
+
+// flattenable reports whether the fields of an embedded struct of type typ are promoted.
+func flattenable(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || typ == timeType {
+		return false
+	}
+	ptr := reflect.PointerTo(typ)
+	return !ptr.Implements(encoderType) && !ptr.Implements(decoderType) &&
+		!ptr.Implements(optionalSetterType) && !ptr.Implements(valuerType)
+}
+
+// dominantField returns the field of group that wins under the encoding/json
+// rules: the shallowest field, or the only tagged one among the shallowest.
+func dominantField(fields []field, group []int) (int, bool) {
+	sorted := append([]int(nil), group...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(fields[sorted[i]].index) < len(fields[sorted[j]].index)
+	})
+	depth := len(fields[sorted[0]].index)
+	var shallowest, tagged []int
+	for _, i := range sorted {
+		if len(fields[i].index) != depth {
+			break
+		}
+		shallowest = append(shallowest, i)
+		if fields[i].tagged {
+			tagged = append(tagged, i)
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0], true
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return 0, false
+}
+
+func fieldPaths(fields []field, group []int) string {
+	paths := make([]string, len(group))
+	for i, j := range group {
+		paths[i] = fields[j].path
+	}
+	return strings.Join(paths, ", ")
+}
+
+// fieldValue returns the value of the field at index in the struct v.
+// Nil embedded pointers are allocated when alloc is set; otherwise the second
+// return value is false if the field is behind a nil embedded pointer.
+func fieldValue(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
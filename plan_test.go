@@ -0,0 +1,141 @@
+package querystring
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type planBase struct {
+	ID      int    `url:"id"`
+	Created string `url:"created"`
+}
+
+type PlanPage struct {
+	Page  int `url:"page"`
+	Limit int `url:"limit"`
+}
+
+func TestPlanEmbedded(t *testing.T) {
+	type Input struct {
+		planBase
+		*PlanPage
+		Name string `url:"name"`
+	}
+	in := Input{planBase: planBase{ID: 1, Created: "now"}, Name: "x"}
+	values, err := Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{"id": {"1"}, "created": {"now"}, "name": {"x"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	var out Input
+	if err := Decode(url.Values{"id": {"2"}, "page": {"3"}}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != 2 || out.PlanPage == nil || out.Page != 3 {
+		t.Errorf("unexpected decoded value %+v", out)
+	}
+}
+
+func TestPlanCollision(t *testing.T) {
+	type Input struct {
+		UserID  int
+		User_ID int
+	}
+	_, err := NewConverter(NewTag()).Values(Input{})
+	if err == nil || !strings.Contains(err.Error(), `duplicate key "user_id"`) {
+		t.Errorf("expected a duplicate key error, got %v", err)
+	}
+	if err := NewConverter(NewTag(), WithDominance(true)).Decode(url.Values{}, &Input{}); err == nil {
+		t.Error("expected an ambiguous key error")
+	}
+}
+
+func TestPlanDominance(t *testing.T) {
+	type Input struct {
+		planBase
+		ID int `url:"id"`
+	}
+	if _, err := Values(Input{}); err == nil {
+		t.Error("expected a duplicate key error without dominance")
+	}
+	values, err := NewConverter(NewTag(), WithDominance(true)).Values(Input{planBase: planBase{ID: 1}, ID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values.Get("id"); got != "2" {
+		t.Errorf("expected the shallow field to win, got %q", got)
+	}
+
+	type Tagged struct {
+		planBase
+		Created string
+	}
+	type Both struct {
+		Tagged
+		PlanPage
+		Extra struct{} `url:"-"`
+	}
+	values, err = NewConverter(NewTag(), WithDominance(true)).Values(Both{Tagged: Tagged{planBase: planBase{Created: "base"}, Created: "outer"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values.Get("created"); got != "outer" {
+		t.Errorf("expected the shallow field to win, got %q", got)
+	}
+}
+
+func TestPlanNameErrors(t *testing.T) {
+	type Input struct {
+		Page  int
+		Type_ string
+	}
+	_, err := NewConverter(NewTag()).Values(Input{})
+	var nameErr *NameError
+	if !errors.As(err, &nameErr) || !strings.Contains(err.Error(), "field Type_") {
+		t.Fatalf("expected a name error for field Type_, got %v", err)
+	}
+	if nameErr.Name != SnakeCase || nameErr.Input != "Type_" {
+		t.Errorf("unexpected name error %+v", nameErr)
+	}
+
+	values, err := NewConverter(NewTag(WithNameFallback(true))).Values(Input{Page: 1, Type_: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{"page": {"1"}, "Type_": {"a"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	_, err = NewConverter(NewTag(WithUseName("kebob"))).Values(Input{})
+	if err == nil || !strings.Contains(err.Error(), `unknown naming strategy "kebob"`) {
+		t.Errorf("expected an unknown naming strategy error, got %v", err)
+	}
+
+	empty := NameConverterFunc(func(string) string { return "" })
+	if _, err := NewConverter(NewTag(WithNameConverter(empty))).Values(Input{}); err == nil {
+		t.Error("expected an empty key error")
+	}
+}
+
+func TestPlanOptionsOnlyTag(t *testing.T) {
+	type Input struct {
+		PageSize int    `url:",omitempty"`
+		Sort     string `url:",omitempty"`
+	}
+	values, err := Values(Input{PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{"page_size": {"10"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
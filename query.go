@@ -1,14 +1,22 @@
 package querystring
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"net/url"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 var timeType = reflect.TypeOf(time.Time{})
 
+// valuerType is the type of driver.Valuer, implemented by the database/sql Null types.
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
 // Encoder is an interface implemented by any type that wishes to encode
 var encoderType = reflect.TypeOf((*Encoder)(nil)).Elem()
 
@@ -20,12 +28,36 @@ type Encoder interface {
 }
 
 type Converter struct {
-	tag Tag
+	tag           Tag
+	style         Style
+	unknownKeys   UnknownKeys
+	unknownReport func(keys []string)
+	dominance     bool
+	groups        []string
+	version       string
+	deprecated    func(key, until string)
+	plans         sync.Map // reflect.Type -> *plan
+	// pack is set on the Converter returned by packer, which ignores the
+	// OpenAPI style options so that the values can be decoded.
+	pack       bool
+	packOnce   sync.Once
+	packedWith *Converter
 }
 
-func NewConverter(tag Tag) *Converter {
+func NewConverter(tag Tag, opts ...ConverterOption) *Converter {
+	opt := defaultConverterOption()
+	for _, o := range opts {
+		o(opt)
+	}
 	return &Converter{
-		tag: tag,
+		tag:           tag,
+		style:         opt.style,
+		unknownKeys:   opt.unknownKeys,
+		unknownReport: opt.unknownReport,
+		dominance:     opt.dominance,
+		groups:        opt.groups,
+		version:       opt.version,
+		deprecated:    opt.deprecated,
 	}
 }
 
@@ -35,8 +67,12 @@ func NewConverter(tag Tag) *Converter {
 // If the value is a map, the key must be a string and the value must be a string.
 // If the value is a struct, the field must have a tag with the key "url" or a custom tag type.
 // The tag value is the name of the field in the url.Values.
-func (c *Converter) Values(v interface{}) (url.Values, error) {
+// The call options Only and Except select the fields of a struct to encode.
+func (c *Converter) Values(v interface{}, opts ...CallOption) (url.Values, error) {
 	if val, ok := v.(url.Values); ok {
+		if len(opts) > 0 {
+			return nil, fmt.Errorf("field selection is not supported for %T", v)
+		}
 		return val, nil
 	}
 	values := make(url.Values)
@@ -45,6 +81,9 @@ func (c *Converter) Values(v interface{}) (url.Values, error) {
 	}
 	vf := reflect.ValueOf(v)
 	if vf.Kind() == reflect.Map {
+		if len(opts) > 0 {
+			return nil, fmt.Errorf("field selection is not supported for %T", v)
+		}
 		if vf.Type().Key().Kind() != reflect.String {
 			return nil, fmt.Errorf("map key must be a string")
 		}
@@ -66,67 +105,249 @@ func (c *Converter) Values(v interface{}) (url.Values, error) {
 	if vf.Kind() != reflect.Struct {
 		return nil, fmt.Errorf("unsupported type %T", vf.Kind())
 	}
-	if err := c.reflectValue(values, vf); err != nil {
+	p, err := c.plan(vf.Type())
+	if err != nil {
+		return nil, err
+	}
+	fields, err := selectFields(p, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.encodeFields(values, vf, "", fields); err != nil {
 		return nil, err
 	}
 	return values, nil
 }
 
-func (c *Converter) reflectValue(values url.Values, val reflect.Value) error {
-	typ := val.Type()
-	for i := 0; i < typ.NumField(); i++ {
-		sf := typ.Field(i)
-		if sf.PkgPath != "" && !sf.Anonymous { // unexported
+func (c *Converter) reflectValue(values url.Values, val reflect.Value, scope string) error {
+	p, err := c.plan(val.Type())
+	if err != nil {
+		return err
+	}
+	return c.encodeFields(values, val, scope, p.fields)
+}
+
+// encodeFields adds the fields of the struct val to values, nested in scope.
+func (c *Converter) encodeFields(values url.Values, val reflect.Value, scope string, fields []field) error {
+	for _, f := range fields {
+		sv, ok := fieldValue(val, f.index, false)
+		if !ok {
 			continue
 		}
-		sv := val.Field(i)
-		tag, ok := c.tag.Get(sf)
-		if !ok {
+		if f.opts.Contains("path") || f.until != "" { // encoded by BuildURL, or deprecated
 			continue
 		}
-		name, opts := c.tag.ParseTag(tag)
-		if opts.Contains("omitempty") && isEmptyValue(sv) {
+		if f.opts.Contains("remain") {
+			if err := checkRemain(f); err != nil {
+				return err
+			}
+			c.encodeRemain(values, scope, sv)
 			continue
 		}
-
-		if sv.Type() == timeType {
-			values.Add(name, valueToString(sv))
+		if f.opts.Contains("omitempty") && isEmptyValue(sv) {
 			continue
 		}
-		if sv.Type().Implements(encoderType) {
-			enc := sv.Interface().(Encoder)
-			encoded, err := enc.Encode()
-			if err != nil {
-				return err
+		ps, ok, err := parseParamStyle(f.opts)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.path, err)
+		}
+		if ok && !c.pack {
+			err = c.encodeParam(values, c.style.nestKey(scope, f.name), sv, ps)
+		} else {
+			err = c.encodeValue(values, c.style.nestKey(scope, f.name), sv)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeValue adds the values of sv to values under the key name.
+func (c *Converter) encodeValue(values url.Values, name string, sv reflect.Value) error {
+	if sv.Kind() == reflect.Struct && sv.Type().Implements(optionalType) {
+		value, present, null := sv.Interface().(optional).optionalGet()
+		if !present {
+			return nil
+		}
+		if null {
+			if !c.style.SkipNull {
+				values.Add(name, c.style.Null)
 			}
-			for _, v := range encoded {
-				values.Add(name, v)
+			return nil
+		}
+		return c.encodeValue(values, name, value)
+	}
+	if sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface {
+		if sv.IsNil() {
+			if !c.style.SkipNull {
+				values.Add(name, c.style.Null)
 			}
-			continue
+			return nil
 		}
-
-		if sv.Kind() == reflect.Ptr {
-			if sv.IsNil() {
-				break
+	}
+	if sv.Type().Implements(encoderType) {
+		enc := sv.Interface().(Encoder)
+		encoded, err := enc.Encode()
+		if err != nil {
+			return err
+		}
+		for _, v := range encoded {
+			values.Add(name, v)
+		}
+		return nil
+	}
+	if sv.Kind() != reflect.Ptr && sv.Kind() != reflect.Interface && sv.Type().Implements(valuerType) {
+		value, err := sv.Interface().(driver.Valuer).Value()
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			if !c.style.SkipNull {
+				values.Add(name, c.style.Null)
 			}
-			sv = sv.Elem()
+			return nil
+		}
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		return c.encodeValue(values, name, reflect.ValueOf(value))
+	}
+
+	if s, ok := c.formatValue(sv); ok {
+		values.Add(name, s)
+		return nil
+	}
+
+	switch sv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return c.encodeValue(values, name, sv.Elem())
+	case reflect.Slice, reflect.Array:
+		return c.encodeList(values, name, sv)
+	case reflect.Struct:
+		if c.style.Nest == NestNone {
+			return nil
+		}
+		return c.reflectValue(values, sv, name)
+	case reflect.Map:
+		if c.style.Nest == NestNone {
+			return nil
+		}
+		return c.encodeMap(values, name, sv)
+	default:
+	}
+	return nil
+}
+
+// formatValue returns the string form of a primitive value or a time.Time.
+// The second return value is false if sv is not a primitive value.
+func (c *Converter) formatValue(sv reflect.Value) (string, bool) {
+	if sv.Type() == timeType {
+		return valueToString(sv), true
+	}
+	switch sv.Kind() {
+	case reflect.Bool:
+		return c.style.formatBool(sv.Bool()), true
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return valueToString(sv), true
+	default:
+		return "", false
+	}
+}
+
+// encodeList adds the elements of the slice or array sv to values
+// according to the array format of the style.
+func (c *Converter) encodeList(values url.Values, name string, sv reflect.Value) error {
+	if c.style.Array == ArrayComma {
+		if sv.Len() == 0 {
+			return nil
 		}
-		switch sv.Kind() {
-		case reflect.Slice, reflect.Array:
-			for index := 0; index < sv.Len(); index++ {
-				values.Add(name, valueToString(sv.Index(index)))
+		parts := make([]string, sv.Len())
+		for i := range parts {
+			s, err := c.listElement(sv.Index(i))
+			if err != nil {
+				return fmt.Errorf("element %d of %q: %w", i, name, err)
 			}
-		case reflect.String:
-			values.Add(name, sv.String())
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			values.Add(name, fmt.Sprintf("%d", sv.Int()))
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			values.Add(name, fmt.Sprintf("%d", sv.Uint()))
-		case reflect.Bool:
-			values.Add(name, fmt.Sprintf("%t", sv.Bool()))
-		default:
-			continue
+			parts[i] = s
+		}
+		values.Add(name, strings.Join(parts, ","))
+		return nil
+	}
+	for i := 0; i < sv.Len(); i++ {
+		if err := c.encodeValue(values, c.style.arrayKey(name, i), sv.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listElement returns the string form of an element of a comma-separated list,
+// unwrapping Optional, Nullable, pointers, Encoder and driver.Valuer values like encodeValue.
+// Absent and null elements are rendered as the Null value of the style.
+// An error is returned if the element is not a primitive value.
+func (c *Converter) listElement(sv reflect.Value) (string, error) {
+	if sv.Kind() == reflect.Struct && sv.Type().Implements(optionalType) {
+		value, present, null := sv.Interface().(optional).optionalGet()
+		if !present || null {
+			return c.style.Null, nil
+		}
+		return c.listElement(value)
+	}
+	if (sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface) && sv.IsNil() {
+		return c.style.Null, nil
+	}
+	if sv.Type().Implements(encoderType) {
+		encoded, err := sv.Interface().(Encoder).Encode()
+		if err != nil {
+			return "", err
+		}
+		if len(encoded) != 1 {
+			return "", fmt.Errorf("encoder returned %d values for a single element", len(encoded))
+		}
+		return encoded[0], nil
+	}
+	if sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface {
+		return c.listElement(sv.Elem())
+	}
+	if sv.Type().Implements(valuerType) {
+		value, err := sv.Interface().(driver.Valuer).Value()
+		if err != nil {
+			return "", err
+		}
+		if value == nil {
+			return c.style.Null, nil
+		}
+		if b, ok := value.([]byte); ok {
+			value = string(b)
+		}
+		return c.listElement(reflect.ValueOf(value))
+	}
+	if s, ok := c.formatValue(sv); ok {
+		return s, nil
+	}
+	return "", fmt.Errorf("unsupported type %s", sv.Type())
+}
 
+// encodeMap adds the entries of the map sv to values, nested under name.
+// The entries are added in the order of their keys.
+func (c *Converter) encodeMap(values url.Values, name string, sv reflect.Value) error {
+	if sv.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("map key must be a string")
+	}
+	keys := sv.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	for _, k := range keys {
+		key, err := c.mapKey(k.String())
+		if err != nil {
+			return fmt.Errorf("map key %q: %w", k.String(), err)
+		}
+		if err := c.encodeValue(values, c.style.nestKey(name, key), sv.MapIndex(k)); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -151,6 +372,11 @@ func isEmptyValue(v reflect.Value) bool {
 	default:
 	}
 
+	if v.Type().Implements(valuerType) {
+		value, err := v.Interface().(driver.Valuer).Value()
+		return err == nil && value == nil
+	}
+
 	type zeroAble interface {
 		IsZero() bool
 	}
@@ -177,7 +403,7 @@ func valueToString(value reflect.Value) string {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return fmt.Sprintf("%d", value.Uint())
 	case reflect.Float32, reflect.Float64:
-		return fmt.Sprintf("%f", value.Float())
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
 	case reflect.Bool:
 		return fmt.Sprintf("%t", value.Bool())
 	default:
@@ -190,7 +416,7 @@ func valueToString(value reflect.Value) string {
 // The value can also implement the Encoder interface.
 // If the value is a map, the key must be a string and the value must be a string.
 // If the value is a struct, the field must have a tag with the key "url".
-func Values(v interface{}) (url.Values, error) {
+func Values(v interface{}, opts ...CallOption) (url.Values, error) {
 	converter := NewConverter(NewTag())
-	return converter.Values(v)
+	return converter.Values(v, opts...)
 }
@@ -1,7 +1,9 @@
 package querystring
 
 import (
+	"database/sql"
 	"net/url"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -109,3 +111,122 @@ func TestStructToValues(t *testing.T) {
 		}
 	}
 }
+
+func TestSQLNullValues(t *testing.T) {
+	type Input struct {
+		Name    sql.NullString   `url:"name"`
+		Count   sql.NullInt64    `url:"count"`
+		Ratio   sql.NullFloat64  `url:"ratio,omitempty"`
+		Active  sql.NullBool     `url:"active"`
+		Since   sql.NullTime     `url:"since"`
+		Owner   sql.Null[string] `url:"owner"`
+		Deleted *sql.NullBool    `url:"deleted"`
+		Parent  sql.NullInt32    `url:"parent"`
+		Tags    sql.Null[int16]  `url:"tags,omitempty"`
+		Bytes   sql.Null[[]byte] `url:"bytes"`
+		Scores  []sql.NullInt64  `url:"scores,omitempty"`
+	}
+	since := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	in := Input{
+		Name:   sql.NullString{String: "alice", Valid: true},
+		Count:  sql.NullInt64{Int64: 0, Valid: true},
+		Active: sql.NullBool{Bool: true, Valid: true},
+		Since:  sql.NullTime{Time: since, Valid: true},
+		Owner:  sql.Null[string]{V: "fred", Valid: true},
+		Bytes:  sql.Null[[]byte]{V: []byte("raw"), Valid: true},
+	}
+	expected := url.Values{
+		"name":   {"alice"},
+		"count":  {"0"},
+		"active": {"true"},
+		"since":  {since.Format(time.RFC3339Nano)},
+		"owner":  {"fred"},
+		"bytes":  {"raw"},
+	}
+	values, err := Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	var out Input
+	if err := Decode(values, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+
+	con := NewConverter(NewTag(), WithStyle(StyleQS))
+	values, err = con.Values(Input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values.Encode(); got != "active=&bytes=&count=&deleted=&name=&owner=&parent=&since=" {
+		t.Errorf("unexpected encoding of invalid values %q", got)
+	}
+	out = Input{Name: sql.NullString{String: "x", Valid: true}}
+	if err := con.Decode(values, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Name.Valid {
+		t.Errorf("expected invalid name, got %+v", out.Name)
+	}
+
+	out = Input{}
+	presence, err := NewConverter(NewTag(), WithStyle(StylePHP)).DecodePresence(url.Values{"name": {"bob"}, "deleted": {"1"}}, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !presence.Has("Name") || out.Name != (sql.NullString{String: "bob", Valid: true}) {
+		t.Errorf("expected present name bob, got %+v (presence %v)", out.Name, presence)
+	}
+	if out.Deleted == nil || *out.Deleted != (sql.NullBool{Bool: true, Valid: true}) {
+		t.Errorf("expected deleted true, got %+v", out.Deleted)
+	}
+
+	in = Input{Scores: []sql.NullInt64{{Int64: 5, Valid: true}, {}, {Int64: 7, Valid: true}}}
+	for _, style := range []Style{StyleDefault, StyleQS, StylePHP, StyleRails} {
+		style.SkipNull = false
+		con := NewConverter(NewTag(), WithStyle(style))
+		values, err := con.Values(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = Input{}
+		if err := con.Decode(values, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out.Scores, in.Scores) {
+			t.Errorf("%+v: expected scores %+v, got %+v", style, in.Scores, out.Scores)
+		}
+	}
+}
+
+func TestFloatRoundTrip(t *testing.T) {
+	type Input struct {
+		Small float64 `url:"small"`
+		Fine  float64 `url:"fine"`
+		Large float64 `url:"large"`
+		Ratio float32 `url:"ratio"`
+		Round float64 `url:"round"`
+	}
+	in := Input{Small: 1e-7, Fine: 0.1234567, Large: 12345678.9, Ratio: 0.1, Round: 1500000}
+	values, err := Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{"small": {"0.0000001"}, "fine": {"0.1234567"}, "large": {"12345678.9"}, "ratio": {"0.1"}, "round": {"1500000"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+	var out Input
+	if err := Decode(values, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+}
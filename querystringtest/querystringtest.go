@@ -0,0 +1,169 @@
+// Package querystringtest provides helpers for testing code that encodes and
+// decodes query strings with the querystring package.
+package querystringtest
+
+import (
+	"flag"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/FPbear/querystring"
+)
+
+// update rewrites the golden files read by AssertGolden instead of comparing them.
+var update = flag.Bool("querystringtest.update", false, "update the golden files of querystringtest.AssertGolden")
+
+// TB is the subset of testing.TB used by the helpers.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+type options struct {
+	converter *querystring.Converter
+}
+
+// Option configures the helpers.
+type Option func(*options)
+
+// WithConverter sets the Converter used to encode and decode.
+// The default is querystring.NewConverter(querystring.NewTag()).
+func WithConverter(converter *querystring.Converter) Option {
+	return func(o *options) {
+		o.converter = converter
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{converter: querystring.NewConverter(querystring.NewTag())}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// AssertEncodes checks that v encodes to the query string expected.
+// The order of the keys is not significant; the order of the values of a key is.
+func AssertEncodes(t TB, v interface{}, expected string, opts ...Option) {
+	t.Helper()
+	o := newOptions(opts)
+	want, err := url.ParseQuery(expected)
+	if err != nil {
+		t.Fatalf("querystringtest: invalid expected query %q: %v", expected, err)
+		return
+	}
+	got, err := o.converter.Values(v)
+	if err != nil {
+		t.Fatalf("querystringtest: encode %T: %v", v, err)
+		return
+	}
+	if cmp := querystring.CompareValues(want, got); !cmp.Equal() {
+		t.Errorf("querystringtest: %T encodes to %q, expected %q:\n%s", v, got.Encode(), expected, cmp)
+	}
+}
+
+// AssertRoundTrip checks that v, a struct or a pointer to a struct, decodes
+// back to an equal value after being encoded.
+func AssertRoundTrip(t TB, v interface{}, opts ...Option) {
+	t.Helper()
+	o := newOptions(opts)
+	values, err := o.converter.Values(v)
+	if err != nil {
+		t.Fatalf("querystringtest: encode %T: %v", v, err)
+		return
+	}
+	want := reflect.ValueOf(v)
+	if want.Kind() == reflect.Ptr {
+		want = want.Elem()
+	}
+	if want.Kind() != reflect.Struct {
+		t.Fatalf("querystringtest: round trip of %T: value must be a struct or a pointer to a struct", v)
+		return
+	}
+	got := reflect.New(want.Type())
+	if err := o.converter.Decode(values, got.Interface()); err != nil {
+		t.Fatalf("querystringtest: decode %q into %T: %v", values.Encode(), v, err)
+		return
+	}
+	if !reflect.DeepEqual(want.Interface(), got.Elem().Interface()) {
+		t.Errorf("querystringtest: %T does not round trip through %q:\nencoded: %+v\ndecoded: %+v",
+			v, values.Encode(), want.Interface(), got.Elem().Interface())
+	}
+}
+
+// AssertGolden checks that v encodes to the query string stored in the golden
+// file at path, one key=value pair per line.
+// When the test binary runs with -querystringtest.update, the golden file is
+// written with the encoded value instead.
+func AssertGolden(t TB, v interface{}, path string, opts ...Option) {
+	t.Helper()
+	o := newOptions(opts)
+	got, err := o.converter.Values(v)
+	if err != nil {
+		t.Fatalf("querystringtest: encode %T: %v", v, err)
+		return
+	}
+	if *update {
+		if err := writeGolden(path, got); err != nil {
+			t.Fatalf("querystringtest: update golden file: %v", err)
+		}
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("querystringtest: read golden file: %v", err)
+		return
+	}
+	want, err := url.ParseQuery(strings.Join(strings.Fields(string(data)), "&"))
+	if err != nil {
+		t.Fatalf("querystringtest: invalid golden file %s: %v", path, err)
+		return
+	}
+	if cmp := querystring.CompareValues(want, got); !cmp.Equal() {
+		t.Errorf("querystringtest: %T does not match golden file %s:\n%s", v, path, cmp)
+	}
+}
+
+func writeGolden(path string, values url.Values) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data := strings.ReplaceAll(values.Encode(), "&", "\n")
+	if data != "" {
+		data += "\n"
+	}
+	return os.WriteFile(path, []byte(data), 0o644)
+}
+
+// Handler returns an http.Handler that checks that the query of every request
+// decodes to expected, a struct or a pointer to a struct, and then calls next.
+// A nil next responds with 204 No Content.
+// Mismatches are reported with t.Errorf, so that the handler can run in the
+// goroutine of an httptest.Server.
+func Handler(t TB, expected interface{}, next http.Handler, opts ...Option) http.Handler {
+	o := newOptions(opts)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Helper()
+		want := reflect.ValueOf(expected)
+		if want.Kind() == reflect.Ptr {
+			want = want.Elem()
+		}
+		got := reflect.New(want.Type())
+		if err := o.converter.Decode(r.URL.Query(), got.Interface()); err != nil {
+			t.Errorf("querystringtest: decode query %q of %s %s: %v", r.URL.RawQuery, r.Method, r.URL.Path, err)
+		} else if !reflect.DeepEqual(want.Interface(), got.Elem().Interface()) {
+			t.Errorf("querystringtest: query %q of %s %s decodes to %+v, expected %+v",
+				r.URL.RawQuery, r.Method, r.URL.Path, got.Elem().Interface(), want.Interface())
+		}
+		if next == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,118 @@
+package querystringtest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FPbear/querystring"
+)
+
+type search struct {
+	Query string   `url:"q"`
+	Page  int      `url:"page"`
+	Limit int      `url:"limit"`
+	Tags  []string `url:"tags,omitempty"`
+}
+
+// recorder is a TB that records failures instead of failing the test.
+type recorder struct {
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	r.fatal = true
+}
+
+func TestAssertEncodes(t *testing.T) {
+	in := search{Query: "go", Page: 2, Limit: 20, Tags: []string{"a", "b"}}
+	AssertEncodes(t, in, "page=2&q=go&limit=20&tags=a&tags=b")
+
+	r := &recorder{}
+	AssertEncodes(r, in, "page=3&q=go&limit=20&tags=b&tags=a")
+	if len(r.errors) != 1 || r.fatal {
+		t.Errorf("expected one error, got %v", r.errors)
+	}
+
+	r = &recorder{}
+	AssertEncodes(r, in, "%zz")
+	if !r.fatal {
+		t.Error("expected a fatal error for an invalid query")
+	}
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	AssertRoundTrip(t, &search{Query: "go", Page: 1, Tags: []string{"x"}})
+	AssertRoundTrip(t, search{Query: "go"}, WithConverter(querystring.NewConverter(querystring.NewTag(), querystring.WithStyle(querystring.StyleQS))))
+
+	type lossy struct {
+		Tags []string `url:"tags"`
+	}
+	r := &recorder{}
+	AssertRoundTrip(r, lossy{Tags: []string{}})
+	if len(r.errors) != 1 {
+		t.Errorf("expected a round trip error, got %v", r.errors)
+	}
+}
+
+func TestAssertGolden(t *testing.T) {
+	in := search{Query: "go lang", Page: 2, Limit: 20}
+	AssertGolden(t, in, filepath.Join("testdata", "search.golden"))
+
+	r := &recorder{}
+	AssertGolden(r, search{Query: "go"}, filepath.Join("testdata", "search.golden"))
+	if len(r.errors) != 1 {
+		t.Errorf("expected a golden mismatch, got %v", r.errors)
+	}
+
+	path := filepath.Join(t.TempDir(), "new", "search.golden")
+	*update = true
+	AssertGolden(t, in, path)
+	*update = false
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "limit=20\npage=2\nq=go+lang\n" {
+		t.Errorf("unexpected golden file %q", data)
+	}
+	AssertGolden(t, in, path)
+}
+
+func TestHandler(t *testing.T) {
+	expected := search{Query: "go", Page: 2}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(Handler(t, expected, next))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/search?q=go&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	r := &recorder{}
+	rec := httptest.NewRecorder()
+	Handler(r, &expected, nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/search?q=rust&page=2", nil))
+	if len(r.errors) != 1 {
+		t.Errorf("expected a query mismatch, got %v", r.errors)
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,189 @@
+package querystring
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// remainType is the type of the field holding the parameters that are not
+// claimed by other fields, selected with the tag option "remain".
+var remainType = reflect.TypeOf(map[string][]string(nil))
+
+func checkRemain(f field) error {
+	if !f.typ.ConvertibleTo(remainType) {
+		return fmt.Errorf("field %s: remain field must be a map[string][]string or url.Values, got %s", f.path, f.typ)
+	}
+	return nil
+}
+
+// encodeRemain adds the entries of the remain map sv to values, nested in scope.
+func (c *Converter) encodeRemain(values url.Values, scope string, sv reflect.Value) {
+	m := sv.Convert(remainType).Interface().(map[string][]string)
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := c.style.nestKey(scope, k)
+		values[key] = append(values[key], m[k]...)
+	}
+}
+
+// claims reports whether key holds a value of the field f of the top-level struct.
+func (c *Converter) claims(key string, f field) bool {
+	if ps, ok, _ := parseParamStyle(f.opts); ok {
+		// OpenAPI styles are not decoded; accept any nested key.
+		if key == f.name || strings.HasPrefix(key, f.name+"[") || strings.HasPrefix(key, f.name+".") {
+			return true
+		}
+		if ps.name == ParamStyleForm && ps.explode {
+			return c.claimsProperty(key, f.typ)
+		}
+		return false
+	}
+	return c.claimsKey(key, f.name, f.typ)
+}
+
+// claimsProperty reports whether key is a property of the struct typ, which an
+// exploded form object writes as a top-level key. The keys of maps are not known
+// in advance and are not claimed.
+func (c *Converter) claimsProperty(key string, typ reflect.Type) bool {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if elem, ok := optionalElem(typ); ok {
+		return c.claimsProperty(key, elem)
+	}
+	if typ.Kind() != reflect.Struct || typ == timeType ||
+		typ.Implements(encoderType) || reflect.PointerTo(typ).Implements(encoderType) {
+		return false
+	}
+	p, err := c.plan(typ)
+	if err != nil {
+		return false
+	}
+	for _, f := range p.fields {
+		if f.name == key && !f.opts.Contains("remain") && f.until == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// claimsKey reports whether key holds a value of type typ under name, either
+// directly or as an element, map entry or nested field according to the style.
+// Nested fields are matched against the plan of their struct, so that a
+// misspelled nested key is not claimed.
+func (c *Converter) claimsKey(key, name string, typ reflect.Type) bool {
+	if key == name {
+		return true
+	}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if elem, ok := optionalElem(typ); ok {
+		return c.claimsKey(key, name, elem)
+	}
+	ptr := reflect.PointerTo(typ)
+	switch {
+	case typ == timeType || ptr.Implements(decoderType) || ptr.Implements(scannerType):
+		return false
+	case typ.Kind() == reflect.Struct:
+		prefix := strings.TrimSuffix(c.style.nestKey(name, ""), "]")
+		if c.style.Nest == NestNone || !strings.HasPrefix(key, prefix) {
+			return false
+		}
+		p, err := c.plan(typ)
+		if err != nil {
+			return false
+		}
+		for _, f := range p.fields {
+			if f.opts.Contains("path") || f.opts.Contains("remain") {
+				continue
+			}
+			if c.claimsKey(key, c.style.nestKey(name, f.name), f.typ) {
+				return true
+			}
+		}
+		return false
+	case typ.Kind() == reflect.Map:
+		prefix := strings.TrimSuffix(c.style.nestKey(name, ""), "]")
+		if c.style.Nest == NestNone || !strings.HasPrefix(key, prefix) {
+			return false
+		}
+		sub := key[len(prefix):]
+		if c.style.Nest == NestBrackets {
+			if !strings.HasSuffix(sub, "]") {
+				return false
+			}
+			sub = sub[:len(sub)-1]
+		}
+		return sub != "" && !strings.ContainsAny(sub, ".[]")
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		switch c.style.Array {
+		case ArrayBrackets:
+			return key == name+"[]"
+		case ArrayIndices:
+			index, ok := strings.CutPrefix(key, name+"[")
+			if !ok || !strings.HasSuffix(index, "]") {
+				return false
+			}
+			_, err := strconv.Atoi(index[:len(index)-1])
+			return err == nil
+		}
+	}
+	return false
+}
+
+// knownKeys returns name and, for nested structs, the keys of their fields,
+// to suggest the closest known key for an unknown one.
+func (c *Converter) knownKeys(name string, typ reflect.Type, visited map[reflect.Type]bool) []string {
+	keys := []string{name}
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if elem, ok := optionalElem(typ); ok {
+		typ = elem
+	}
+	ptr := reflect.PointerTo(typ)
+	if c.style.Nest == NestNone || typ.Kind() != reflect.Struct || typ == timeType ||
+		ptr.Implements(decoderType) || ptr.Implements(scannerType) || visited[typ] {
+		return keys
+	}
+	p, err := c.plan(typ)
+	if err != nil {
+		return keys
+	}
+	visited[typ] = true
+	for _, f := range p.fields {
+		if f.opts.Contains("path") || f.opts.Contains("remain") {
+			continue
+		}
+		keys = append(keys, c.knownKeys(c.style.nestKey(name, f.name), f.typ, visited)...)
+	}
+	delete(visited, typ)
+	return keys
+}
+
+// unclaimed returns the values whose keys are not claimed by any of the fields.
+func (c *Converter) unclaimed(values url.Values, fields []field) url.Values {
+	remain := make(url.Values)
+	for k, vs := range values {
+		claimed := false
+		for _, f := range fields {
+			if c.claims(k, f) {
+				claimed = true
+				break
+			}
+		}
+		if !claimed {
+			remain[k] = append([]string(nil), vs...)
+		}
+	}
+	return remain
+}
@@ -0,0 +1,59 @@
+package querystring
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestRemain(t *testing.T) {
+	type Input struct {
+		Page  int        `url:"page"`
+		Tags  []string   `url:"tags"`
+		Extra url.Values `url:",remain"`
+	}
+	in := Input{
+		Page:  2,
+		Tags:  []string{"a"},
+		Extra: url.Values{"utm_source": {"mail"}, "tags": {"b"}},
+	}
+	values, err := NewConverter(NewTag(), WithStyle(StyleRails)).Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{
+		"page":       {"2"},
+		"tags[]":     {"a"},
+		"tags":       {"b"},
+		"utm_source": {"mail"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	var out Input
+	values = url.Values{"page": {"3"}, "tags[]": {"x", "y"}, "ref": {"home"}, "debug": {"1", "2"}}
+	if err := NewConverter(NewTag(), WithStyle(StyleRails)).Decode(values, &out); err != nil {
+		t.Fatal(err)
+	}
+	expectedOut := Input{
+		Page:  3,
+		Tags:  []string{"x", "y"},
+		Extra: url.Values{"ref": {"home"}, "debug": {"1", "2"}},
+	}
+	if !reflect.DeepEqual(out, expectedOut) {
+		t.Errorf("expected %+v, got %+v", expectedOut, out)
+	}
+}
+
+func TestRemainType(t *testing.T) {
+	type Input struct {
+		Extra map[string]string `url:",remain"`
+	}
+	if _, err := Values(Input{}); err == nil {
+		t.Error("expected an error for an invalid remain field")
+	}
+	if err := Decode(url.Values{}, &Input{}); err == nil {
+		t.Error("expected an error for an invalid remain field")
+	}
+}
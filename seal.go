@@ -0,0 +1,130 @@
+package querystring
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/url"
+)
+
+// ErrInvalidToken is returned by Open when a token cannot be decrypted,
+// either because it was tampered with or because it was sealed with another key.
+var ErrInvalidToken = errors.New("token is invalid")
+
+// packStyle is the style used to pack whole structs into tokens and cursors:
+// nested structs and maps are kept, arrays are indexed, and nil values are
+// encoded with a sentinel that does not occur in text.
+var packStyle = Style{
+	Nest:  NestBrackets,
+	Array: ArrayIndices,
+	True:  "true",
+	False: "false",
+	Null:  "\x00",
+}
+
+// packer returns a Converter with the tag and options of c that encodes with
+// packStyle and ignores the OpenAPI style options, so that the values of a
+// struct decode back into it whatever the style of c.
+func (c *Converter) packer() *Converter {
+	c.packOnce.Do(func() {
+		c.packedWith = &Converter{
+			tag:           c.tag,
+			style:         packStyle,
+			unknownKeys:   c.unknownKeys,
+			unknownReport: c.unknownReport,
+			dominance:     c.dominance,
+			groups:        c.groups,
+			version:       c.version,
+			deprecated:    c.deprecated,
+			pack:          true,
+		}
+	})
+	return c.packedWith
+}
+
+// Seal packs v into a single tamper-proof token.
+// v is converted like Values, but with nested structs, maps and arrays kept
+// whatever the style of the Converter, then compressed with DEFLATE, encrypted
+// with AES-GCM and base64url-encoded. The key must be 16, 24 or 32 bytes long.
+func (c *Converter) Seal(v interface{}, key []byte) (string, error) {
+	values, err := c.packer().Values(v)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write([]byte(values.Encode())); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+buf.Len()+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, buf.Bytes(), nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Open unpacks a token created by Seal into the struct pointed to by dst.
+func (c *Converter) Open(token string, dst interface{}, key []byte) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return ErrInvalidToken
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	compressed, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	query, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(string(query))
+	if err != nil {
+		return err
+	}
+	return c.packer().Decode(values, dst)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal packs v into a single tamper-proof token.
+// The fields must have a tag with the key "url".
+func Seal(v interface{}, key []byte) (string, error) {
+	converter := NewConverter(NewTag())
+	return converter.Seal(v, key)
+}
+
+// Open unpacks a token created by Seal into the struct pointed to by dst.
+// The fields must have a tag with the key "url".
+func Open(token string, dst interface{}, key []byte) error {
+	converter := NewConverter(NewTag())
+	return converter.Open(token, dst, key)
+}
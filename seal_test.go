@@ -0,0 +1,81 @@
+package querystring
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	type State struct {
+		Redirect string   `url:"redirect"`
+		Nonce    int64    `url:"nonce"`
+		Scopes   []string `url:"scopes"`
+	}
+	key := []byte("0123456789abcdef0123456789abcdef")
+	in := State{Redirect: "https://example.com/callback?x=1", Nonce: 42, Scopes: []string{"read", "write"}}
+
+	token, err := Seal(in, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(token, "+/=") {
+		t.Errorf("token %q is not base64url without padding", token)
+	}
+	var out State
+	if err := Open(token, &out, key); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+
+	tampered := []byte(token)
+	tampered[len(tampered)/2] ^= 1
+	if err := Open(string(tampered), &out, key); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+	if err := Open(token, &out, []byte("fedcba9876543210fedcba9876543210")); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken, got %v", err)
+	}
+	if _, err := Seal(in, []byte("short")); err == nil {
+		t.Error("expected an error for an invalid key size")
+	}
+}
+
+func TestSealNested(t *testing.T) {
+	type Address struct {
+		City string `url:"city"`
+	}
+	type State struct {
+		Address Address           `url:"address"`
+		Labels  map[string]string `url:"labels"`
+		Parent  *Address          `url:"parent"`
+		Note    *string           `url:"note"`
+		Owner   Nullable[string]  `url:"owner"`
+		Tags    []string          `url:"tags,style=pipeDelimited,explode=false"`
+		N       int               `url:"n"`
+	}
+	empty := ""
+	in := State{
+		Address: Address{City: "Paris"},
+		Labels:  map[string]string{"team": "core"},
+		Note:    &empty,
+		Owner:   NullOf[string](),
+		Tags:    []string{"a", "b"},
+		N:       1,
+	}
+	key := []byte("0123456789abcdef")
+	token, err := Seal(in, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out State
+	if err := Open(token, &out, key); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+}
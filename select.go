@@ -0,0 +1,45 @@
+package querystring
+
+import "fmt"
+
+// selectFields returns the fields of p selected by the call options.
+// An error is returned for a selector that matches no field, with the closest
+// field path or query name as a suggestion.
+func selectFields(p *plan, opts []CallOption) ([]field, error) {
+	if len(opts) == 0 {
+		return p.fields, nil
+	}
+	o := &callOption{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var known []string
+	for _, f := range p.fields {
+		known = append(known, f.path, f.name)
+	}
+	for _, selector := range append(append([]string(nil), o.only...), o.except...) {
+		if containsString(known, selector) {
+			continue
+		}
+		if s, ok := suggest(selector, known); ok {
+			return nil, fmt.Errorf("unknown field %q (did you mean %q?)", selector, s)
+		}
+		return nil, fmt.Errorf("unknown field %q", selector)
+	}
+
+	selects := func(selectors []string, f field) bool {
+		return containsString(selectors, f.path) || containsString(selectors, f.name)
+	}
+	var fields []field
+	for _, f := range p.fields {
+		if o.only != nil && !selects(o.only, f) {
+			continue
+		}
+		if selects(o.except, f) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
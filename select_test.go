@@ -0,0 +1,79 @@
+package querystring
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelectFields(t *testing.T) {
+	type Input struct {
+		planBase
+		Page  int    `url:"page"`
+		Limit int    `url:"limit"`
+		Debug bool   `url:"debug"`
+		Query string `url:"q"`
+	}
+	in := Input{planBase: planBase{ID: 1}, Page: 2, Limit: 10, Debug: true, Query: "go"}
+
+	tests := []struct {
+		opts   []CallOption
+		expect url.Values
+	}{
+		{[]CallOption{Only("page", "Limit")}, url.Values{"page": {"2"}, "limit": {"10"}}},
+		{[]CallOption{Only("planBase.ID")}, url.Values{"id": {"1"}}},
+		{[]CallOption{Except("debug", "Query", "created")}, url.Values{"id": {"1"}, "page": {"2"}, "limit": {"10"}}},
+		{[]CallOption{Only("page", "limit"), Except("limit")}, url.Values{"page": {"2"}}},
+	}
+	for _, test := range tests {
+		values, err := Values(in, test.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(values, test.expect) {
+			t.Errorf("expected %v, got %v", test.expect, values)
+		}
+	}
+
+	_, err := Values(in, Only("limt"))
+	if err == nil || !strings.Contains(err.Error(), `unknown field "limt" (did you mean "limit"?)`) {
+		t.Errorf("expected an unknown field error, got %v", err)
+	}
+	if _, err := Values(map[string]string{"a": "b"}, Except("a")); err == nil {
+		t.Error("expected an error for field selection on a map")
+	}
+}
+
+func TestSelectFieldsDecode(t *testing.T) {
+	type Input struct {
+		Page  int  `url:"page"`
+		Debug bool `url:"debug"`
+	}
+	query := url.Values{"page": {"2"}, "debug": {"true"}}
+
+	var out Input
+	if err := Decode(query, &out, Except("debug")); err != nil {
+		t.Fatal(err)
+	}
+	if out != (Input{Page: 2}) {
+		t.Errorf("unexpected decoded value %+v", out)
+	}
+
+	c := NewConverter(NewTag(), WithUnknownKeys(RejectUnknownKeys))
+	if err := c.Decode(query, &Input{}, Only("Page")); err == nil {
+		t.Error("expected an unknown keys error for the excluded field")
+	}
+
+	presence, err := NewConverter(NewTag()).DecodePresence(query, &Input{}, Only("debug"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if presence.Has("Page") || !presence.Has("Debug") {
+		t.Errorf("unexpected presence %v", presence)
+	}
+
+	if err := Decode(query, &Input{}, Except("Debgu")); err == nil {
+		t.Error("expected an unknown field error")
+	}
+}
@@ -0,0 +1,113 @@
+package querystring
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// The parameters added to signed values.
+const (
+	ExpiresParam   = "expires"
+	KeyIDParam     = "kid"
+	SignatureParam = "signature"
+)
+
+var (
+	ErrSignatureMissing = errors.New("signature is missing")
+	ErrSignatureInvalid = errors.New("signature is invalid")
+	ErrSignatureExpired = errors.New("signature has expired")
+	ErrUnknownKey       = errors.New("signing key is unknown")
+)
+
+// signedProfile is the canonical form covered by the signature.
+var signedProfile = CanonicalProfile{
+	Exclude: []string{SignatureParam},
+}
+
+// Signer signs url.Values with HMAC-SHA256 and verifies signed values.
+// The signature covers the canonical query string of every parameter except
+// the signature itself, including the expiry time and the key ID.
+type Signer struct {
+	converter *Converter
+	keyID     string
+	keys      map[string][]byte
+	now       func() time.Time
+}
+
+// NewSigner returns a Signer that converts values with converter and signs
+// them with the key identified by keyID.
+func NewSigner(converter *Converter, keyID string, key []byte, opts ...SignerOption) *Signer {
+	opt := defaultSignerOption()
+	for _, o := range opts {
+		o(opt)
+	}
+	opt.keys[keyID] = key
+	return &Signer{
+		converter: converter,
+		keyID:     keyID,
+		keys:      opt.keys,
+		now:       opt.now,
+	}
+}
+
+// Sign converts v to url.Values and signs them, see SignValues.
+func (s *Signer) Sign(v interface{}, ttl time.Duration) (url.Values, error) {
+	values, err := s.converter.Values(v)
+	if err != nil {
+		return nil, err
+	}
+	return s.SignValues(values, ttl), nil
+}
+
+// SignValues returns a copy of values with the expires, kid and signature
+// parameters added. The signature expires after ttl.
+func (s *Signer) SignValues(values url.Values, ttl time.Duration) url.Values {
+	signed := make(url.Values, len(values)+3)
+	for k, vs := range values {
+		signed[k] = append([]string(nil), vs...)
+	}
+	signed.Set(ExpiresParam, strconv.FormatInt(s.now().Add(ttl).Unix(), 10))
+	signed.Set(KeyIDParam, s.keyID)
+	signed.Set(SignatureParam, s.signature(s.keys[s.keyID], signed))
+	return signed
+}
+
+// Verify checks the signature and the expiry time of signed values.
+func (s *Signer) Verify(values url.Values) error {
+	signature := values.Get(SignatureParam)
+	if signature == "" {
+		return ErrSignatureMissing
+	}
+	key, ok := s.keys[values.Get(KeyIDParam)]
+	if !ok {
+		return ErrUnknownKey
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.signature(key, values))) {
+		return ErrSignatureInvalid
+	}
+	expires, err := strconv.ParseInt(values.Get(ExpiresParam), 10, 64)
+	if err != nil {
+		return ErrSignatureInvalid
+	}
+	if s.now().Unix() > expires {
+		return ErrSignatureExpired
+	}
+	return nil
+}
+
+// VerifyRequest checks the signature and the expiry time of the query of r.
+func (s *Signer) VerifyRequest(r *http.Request) error {
+	return s.Verify(r.URL.Query())
+}
+
+func (s *Signer) signature(key []byte, values url.Values) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(Canonicalize(values, signedProfile)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
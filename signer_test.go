@@ -0,0 +1,61 @@
+package querystring
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	type Input struct {
+		File string `url:"file"`
+		User int    `url:"user"`
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	signer := NewSigner(NewConverter(NewTag()), "k2", []byte("secret-2"),
+		WithClock(clock), WithVerifyKey("k1", []byte("secret-1")))
+
+	values, err := signer.Sign(Input{File: "report.pdf", User: 7}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Get(KeyIDParam) != "k2" || values.Get(ExpiresParam) != "1704070800" {
+		t.Fatalf("unexpected signed values %v", values)
+	}
+	if err := signer.Verify(values); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+
+	r := httptest.NewRequest("GET", "/download?"+values.Encode(), nil)
+	if err := signer.VerifyRequest(r); err != nil {
+		t.Errorf("expected valid request, got %v", err)
+	}
+
+	tampered := signer.SignValues(values, time.Hour)
+	tampered.Set("user", "8")
+	if err := signer.Verify(tampered); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected ErrSignatureInvalid, got %v", err)
+	}
+
+	old := NewSigner(NewConverter(NewTag()), "k1", []byte("secret-1"), WithClock(clock))
+	if err := signer.Verify(old.SignValues(values, time.Hour)); err != nil {
+		t.Errorf("expected rotated key to verify, got %v", err)
+	}
+
+	unknown := NewSigner(NewConverter(NewTag()), "k0", []byte("secret-0"), WithClock(clock))
+	if err := signer.Verify(unknown.SignValues(values, time.Hour)); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if err := signer.Verify(values); !errors.Is(err, ErrSignatureExpired) {
+		t.Errorf("expected ErrSignatureExpired, got %v", err)
+	}
+
+	values.Del(SignatureParam)
+	if err := signer.Verify(values); !errors.Is(err, ErrSignatureMissing) {
+		t.Errorf("expected ErrSignatureMissing, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package querystring
+
+import "strconv"
+
+// NestFormat controls how the keys of nested structs and maps are built.
+type NestFormat int
+
+const (
+	// NestNone skips nested structs and maps.
+	NestNone NestFormat = iota
+	// NestBrackets wraps nested keys in brackets: a[b]=1.
+	NestBrackets
+	// NestDots joins nested keys with a dot: a.b=1.
+	NestDots
+)
+
+// ArrayFormat controls how the elements of slices and arrays are keyed.
+type ArrayFormat int
+
+const (
+	// ArrayRepeat repeats the key for every element: a=1&a=2.
+	ArrayRepeat ArrayFormat = iota
+	// ArrayBrackets appends empty brackets to the key: a[]=1&a[]=2.
+	ArrayBrackets
+	// ArrayIndices appends the element index to the key: a[0]=1&a[1]=2.
+	ArrayIndices
+	// ArrayComma joins all elements into a single value: a=1,2.
+	ArrayComma
+)
+
+// Style describes how a Converter renders nested values, arrays,
+// booleans and nil values.
+type Style struct {
+	// Nest is the format used for nested structs and maps.
+	Nest NestFormat
+	// Array is the format used for slices and arrays.
+	Array ArrayFormat
+	// True and False are the values used for booleans.
+	True  string
+	False string
+	// Null is the value used for nil pointers and interfaces.
+	// It is ignored when SkipNull is set.
+	Null string
+	// SkipNull omits nil pointers and interfaces.
+	SkipNull bool
+}
+
+var (
+	// StyleDefault is the style used when no other style is configured.
+	// Nested structs and maps are skipped and array keys are repeated.
+	StyleDefault = Style{
+		Nest:     NestNone,
+		Array:    ArrayRepeat,
+		True:     "true",
+		False:    "false",
+		SkipNull: true,
+	}
+	// StylePHP matches the output of PHP's http_build_query.
+	StylePHP = Style{
+		Nest:     NestBrackets,
+		Array:    ArrayIndices,
+		True:     "1",
+		False:    "0",
+		SkipNull: true,
+	}
+	// StyleQS matches the output of the Node qs package's stringify with its default options.
+	StyleQS = Style{
+		Nest:  NestBrackets,
+		Array: ArrayIndices,
+		True:  "true",
+		False: "false",
+		Null:  "",
+	}
+	// StyleRails matches the output of Rails' Hash#to_query.
+	StyleRails = Style{
+		Nest:  NestBrackets,
+		Array: ArrayBrackets,
+		True:  "true",
+		False: "false",
+		Null:  "",
+	}
+	// StyleOpenAPIDeepObject matches the OpenAPI deepObject style for objects
+	// and the exploded form style for arrays.
+	StyleOpenAPIDeepObject = Style{
+		Nest:     NestBrackets,
+		Array:    ArrayRepeat,
+		True:     "true",
+		False:    "false",
+		SkipNull: true,
+	}
+)
+
+// nestKey returns the key of the field name nested in scope.
+func (s Style) nestKey(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	if s.Nest == NestDots {
+		return scope + "." + name
+	}
+	return scope + "[" + name + "]"
+}
+
+// arrayKey returns the key of the element at index i of the array name.
+func (s Style) arrayKey(name string, i int) string {
+	switch s.Array {
+	case ArrayBrackets:
+		return name + "[]"
+	case ArrayIndices:
+		return name + "[" + strconv.Itoa(i) + "]"
+	default:
+		return name
+	}
+}
+
+// formatBool returns the rendering of b.
+func (s Style) formatBool(b bool) string {
+	if b {
+		return s.True
+	}
+	return s.False
+}
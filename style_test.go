@@ -0,0 +1,159 @@
+package querystring
+
+import (
+	"database/sql"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type styleAddress struct {
+	City string `url:"city"`
+	Zip  string `url:"zip"`
+}
+
+type styleInput struct {
+	Name    string       `url:"name"`
+	Tags    []string     `url:"tags"`
+	Active  bool         `url:"active"`
+	Deleted bool         `url:"deleted"`
+	Parent  *string      `url:"parent"`
+	Address styleAddress `url:"address"`
+}
+
+// The golden files hold the output of the reference libraries for the
+// equivalent of styleInput:
+//
+//	php.golden:                http_build_query($input)
+//	qs.golden:                 qs.stringify(input)
+//	rails.golden:              input.to_query
+//	openapi_deepobject.golden: the OpenAPI deepObject serialization
+func TestStyleGolden(t *testing.T) {
+	in := styleInput{
+		Name:    "alice",
+		Tags:    []string{"a", "b"},
+		Active:  true,
+		Deleted: false,
+		Address: styleAddress{City: "Paris", Zip: "75001"},
+	}
+	tests := []struct {
+		golden string
+		style  Style
+	}{
+		{"php.golden", StylePHP},
+		{"qs.golden", StyleQS},
+		{"rails.golden", StyleRails},
+		{"openapi_deepobject.golden", StyleOpenAPIDeepObject},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "style", tt.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, err := url.ParseQuery(strings.TrimSpace(string(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			values, err := NewConverter(NewTag(), WithStyle(tt.style)).Values(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(values, expected) {
+				t.Errorf("expected %v, got %v", expected, values)
+			}
+		})
+	}
+}
+
+func TestStyleDefaultSkipsNested(t *testing.T) {
+	in := styleInput{
+		Name:    "alice",
+		Tags:    []string{"a", "b"},
+		Active:  true,
+		Address: styleAddress{City: "Paris"},
+	}
+	values, err := Values(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{
+		"name":    {"alice"},
+		"tags":    {"a", "b"},
+		"active":  {"true"},
+		"deleted": {"false"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestStyleArrayComma(t *testing.T) {
+	type Input struct {
+		IDs   []int  `url:"ids"`
+		Flags []bool `url:"flags"`
+	}
+	style := StyleDefault
+	style.Array = ArrayComma
+	values, err := NewConverter(NewTag(), WithStyle(style)).Values(Input{
+		IDs:   []int{1, 2, 3},
+		Flags: []bool{true, false},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values.Encode(); got != "flags=true%2Cfalse&ids=1%2C2%2C3" {
+		t.Errorf("unexpected encoding %q", got)
+	}
+}
+
+func TestStyleArrayCommaElements(t *testing.T) {
+	type Input struct {
+		Ptrs  []*int          `url:"ptrs"`
+		Nulls []sql.NullInt64 `url:"nulls"`
+		Opts  []Optional[int] `url:"opts"`
+	}
+	style := StyleDefault
+	style.Array = ArrayComma
+	con := NewConverter(NewTag(), WithStyle(style))
+	one := 1
+	values, err := con.Values(Input{
+		Ptrs:  []*int{&one, nil},
+		Nulls: []sql.NullInt64{{Int64: 5, Valid: true}, {}},
+		Opts:  []Optional[int]{Some(3)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := url.Values{"ptrs": {"1,"}, "nulls": {"5,"}, "opts": {"3"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+
+	type Nested struct {
+		Addrs []styleAddress `url:"addrs"`
+	}
+	if _, err := con.Values(Nested{Addrs: []styleAddress{{}}}); err == nil {
+		t.Error("expected an error for a list of structs")
+	}
+}
+
+func TestStyleNestDots(t *testing.T) {
+	type Input struct {
+		Filter map[string]string `url:"filter"`
+	}
+	style := StyleDefault
+	style.Nest = NestDots
+	values, err := NewConverter(NewTag(), WithStyle(style)).Values(Input{
+		Filter: map[string]string{"b": "2", "a": "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := values.Encode(); got != "filter.a=1&filter.b=2" {
+		t.Errorf("unexpected encoding %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package querystring
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 )
@@ -19,6 +20,17 @@ func (o TagOptions) Contains(option string) bool {
 	return false
 }
 
+// Value returns the value of the option in the form key=value and whether
+// the option is present.
+func (o TagOptions) Value(key string) (string, bool) {
+	for _, s := range o {
+		if k, v, ok := strings.Cut(s, "="); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
 // Tag represents the tag interface.
 // The tag interface provides the methods to get the tag value, parse the tag, and skip the field.
 // The tag value is the value of the tag in the struct field.
@@ -38,10 +50,18 @@ type Tag interface {
 // The tag type is the type of the tag in the struct field.
 // The skip field value is the value of the tag to skip the field.
 // The useName is the name to use when the tag is empty.
+// The acronyms are the words the useName renders in upper case.
+// The converter, if set, is used instead of the useName.
+// The fallback reports whether the Go field name is used when the conversion fails.
+// The normalize reports whether explicit tag names and map keys are converted too.
 type defaultTag struct {
-	tagType string
-	skip    string
-	useName Name
+	tagType   string
+	skip      string
+	useName   Name
+	acronyms  Acronyms
+	converter NameConverter
+	fallback  bool
+	normalize bool
 }
 
 func NewTag(opts ...Option) Tag {
@@ -50,21 +70,78 @@ func NewTag(opts ...Option) Tag {
 		o(opt)
 	}
 	return &defaultTag{
-		tagType: opt.tag,
-		skip:    opt.skipField,
-		useName: opt.useName,
+		tagType:   opt.tag,
+		skip:      opt.skipField,
+		useName:   opt.useName,
+		acronyms:  opt.acronyms,
+		converter: opt.converter,
+		fallback:  opt.fallback,
+		normalize: opt.normalize,
 	}
 }
 
 func (t *defaultTag) Get(field reflect.StructField) (string, bool) {
+	tag, ok, _ := t.Lookup(field)
+	return tag, ok
+}
+
+// Lookup is like Get, but reports an error if the tag has no name and the
+// field name cannot be converted.
+// A tag with options but no name, such as ",omitempty", is named after the field.
+func (t *defaultTag) Lookup(field reflect.StructField) (string, bool, error) {
 	tag := field.Tag.Get(t.tagType)
 	if tag == t.skip {
-		return "", false
+		return "", false, nil
 	}
-	if tag == "" && !t.useName.IsEmpty() {
-		return t.useName.Convert(field.Name), true
+	if name, _ := t.ParseTag(tag); name != "" {
+		key, err := t.NormalizeKey(name)
+		if err != nil {
+			return "", true, err
+		}
+		return key + tag[len(name):], true, nil
 	}
-	return tag, true
+	name, err := t.convert(field.Name)
+	if err != nil {
+		if !t.fallback {
+			return "", true, err
+		}
+		name = field.Name
+	}
+	return name + tag, true, nil
+}
+
+// NormalizeKey converts the key with the configured Name or NameConverter if the
+// tag normalizes keys, and returns it unchanged otherwise.
+func (t *defaultTag) NormalizeKey(key string) (string, error) {
+	if !t.normalize {
+		return key, nil
+	}
+	converted, err := t.convert(key)
+	if err != nil {
+		if t.fallback {
+			return key, nil
+		}
+		return "", err
+	}
+	return converted, nil
+}
+
+// convert converts the Go field name to the key of a field without a tag name.
+func (t *defaultTag) convert(name string) (string, error) {
+	if t.converter == nil {
+		return t.useName.TryConvertWith(name, t.acronyms)
+	}
+	converted := t.converter.Convert(name)
+	if converted == "" {
+		return "", fmt.Errorf("name converter returned an empty key for %q", name)
+	}
+	return converted, nil
+}
+
+// Explicit reports whether the field has a tag of the tag type with a name.
+func (t *defaultTag) Explicit(field reflect.StructField) bool {
+	name, _ := t.ParseTag(field.Tag.Get(t.tagType))
+	return name != "" && name != t.skip
 }
 
 func (t *defaultTag) ParseTag(tag string) (string, TagOptions) {
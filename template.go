@@ -0,0 +1,370 @@
+package querystring
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Template is a URI Template as defined by RFC 6570, up to and including level 4.
+// A Template is expanded with the fields of a struct, named by the Tag of the Converter.
+type Template struct {
+	raw   string
+	parts []templatePart
+}
+
+// templatePart is either a literal or an expression of a template.
+type templatePart struct {
+	literal string
+	expr    *templateExpr
+}
+
+type templateExpr struct {
+	op   templateOp
+	vars []templateVarSpec
+}
+
+type templateVarSpec struct {
+	name    string
+	prefix  int
+	explode bool
+}
+
+// templateOp describes the expansion behavior of an expression operator,
+// as listed in appendix A of RFC 6570.
+type templateOp struct {
+	first         string
+	sep           string
+	named         bool
+	ifEmpty       string
+	allowReserved bool
+}
+
+var templateOps = map[byte]templateOp{
+	0:   {sep: ","},
+	'+': {sep: ",", allowReserved: true},
+	'.': {first: ".", sep: "."},
+	'/': {first: "/", sep: "/"},
+	';': {first: ";", sep: ";", named: true},
+	'?': {first: "?", sep: "&", named: true, ifEmpty: "="},
+	'&': {first: "&", sep: "&", named: true, ifEmpty: "="},
+	'#': {first: "#", sep: ",", allowReserved: true},
+}
+
+// ParseTemplate parses a URI Template.
+func ParseTemplate(s string) (*Template, error) {
+	t := &Template{raw: s}
+	for len(s) > 0 {
+		i := strings.IndexAny(s, "{}")
+		if i < 0 {
+			t.parts = append(t.parts, templatePart{literal: s})
+			break
+		}
+		if s[i] == '}' {
+			return nil, fmt.Errorf("unexpected '}' in template %q", t.raw)
+		}
+		if i > 0 {
+			t.parts = append(t.parts, templatePart{literal: s[:i]})
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed expression in template %q", t.raw)
+		}
+		expr, err := parseTemplateExpr(s[i+1 : i+end])
+		if err != nil {
+			return nil, fmt.Errorf("template %q: %w", t.raw, err)
+		}
+		t.parts = append(t.parts, templatePart{expr: expr})
+		s = s[i+end+1:]
+	}
+	return t, nil
+}
+
+func parseTemplateExpr(s string) (*templateExpr, error) {
+	if s == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	expr := &templateExpr{}
+	op, ok := templateOps[s[0]]
+	if ok {
+		s = s[1:]
+	} else {
+		if strings.IndexByte("=,!@|", s[0]) >= 0 {
+			return nil, fmt.Errorf("reserved operator %q", s[0])
+		}
+		op = templateOps[0]
+	}
+	expr.op = op
+	for _, spec := range strings.Split(s, ",") {
+		v := templateVarSpec{name: spec}
+		if strings.HasSuffix(spec, "*") {
+			v.name = spec[:len(spec)-1]
+			v.explode = true
+		} else if name, prefix, ok := strings.Cut(spec, ":"); ok {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n <= 0 || n >= 10000 {
+				return nil, fmt.Errorf("invalid prefix in %q", spec)
+			}
+			v.name = name
+			v.prefix = n
+		}
+		if !validTemplateVarName(v.name) {
+			return nil, fmt.Errorf("invalid variable name %q", v.name)
+		}
+		expr.vars = append(expr.vars, v)
+	}
+	return expr, nil
+}
+
+func validTemplateVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '.' && i > 0:
+		case c == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the template as it was parsed.
+func (t *Template) String() string {
+	return t.raw
+}
+
+// templateValue is the value of a template variable: a string, a list or
+// an associative array stored as alternating names and values.
+type templateValue struct {
+	str   string
+	list  []string
+	assoc []string
+	kind  templateKind
+}
+
+type templateKind int
+
+const (
+	templateString templateKind = iota + 1
+	templateList
+	templateAssoc
+)
+
+// ExpandTemplate expands the template t with the fields of the struct v.
+// Fields are named as in Values; nil pointers, empty slices and empty maps are undefined.
+func (c *Converter) ExpandTemplate(t *Template, v interface{}) (string, error) {
+	vars, err := c.templateVars(v)
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	for _, part := range t.parts {
+		if part.expr == nil {
+			builder.WriteString(percentEncode(part.literal, true))
+			continue
+		}
+		if err := part.expr.expand(&builder, vars); err != nil {
+			return "", fmt.Errorf("template %q: %w", t.raw, err)
+		}
+	}
+	return builder.String(), nil
+}
+
+// Expand parses the template and expands it with the fields of the struct v.
+func (c *Converter) Expand(template string, v interface{}) (string, error) {
+	t, err := ParseTemplate(template)
+	if err != nil {
+		return "", err
+	}
+	return c.ExpandTemplate(t, v)
+}
+
+func (e *templateExpr) expand(builder *strings.Builder, vars map[string]templateValue) error {
+	op := e.op
+	first := true
+	for _, spec := range e.vars {
+		value, ok := vars[spec.name]
+		if !ok {
+			continue
+		}
+		if first {
+			builder.WriteString(op.first)
+			first = false
+		} else {
+			builder.WriteString(op.sep)
+		}
+
+		if value.kind == templateString {
+			s := value.str
+			if spec.prefix > 0 && utf8.RuneCountInString(s) > spec.prefix {
+				s = string([]rune(s)[:spec.prefix])
+			}
+			if op.named {
+				builder.WriteString(percentEncode(spec.name, true))
+				if s == "" {
+					builder.WriteString(op.ifEmpty)
+					continue
+				}
+				builder.WriteByte('=')
+			}
+			builder.WriteString(percentEncode(s, op.allowReserved))
+			continue
+		}
+
+		if spec.prefix > 0 {
+			return fmt.Errorf("prefix modifier applied to composite variable %q", spec.name)
+		}
+		if !spec.explode {
+			if op.named {
+				builder.WriteString(percentEncode(spec.name, true))
+				builder.WriteByte('=')
+			}
+			items := value.list
+			if value.kind == templateAssoc {
+				items = value.assoc
+			}
+			for i, item := range items {
+				if i > 0 {
+					builder.WriteByte(',')
+				}
+				builder.WriteString(percentEncode(item, op.allowReserved))
+			}
+			continue
+		}
+
+		if value.kind == templateList {
+			for i, item := range value.list {
+				if i > 0 {
+					builder.WriteString(op.sep)
+				}
+				if op.named {
+					builder.WriteString(percentEncode(spec.name, true))
+					if item == "" {
+						builder.WriteString(op.ifEmpty)
+						continue
+					}
+					builder.WriteByte('=')
+				}
+				builder.WriteString(percentEncode(item, op.allowReserved))
+			}
+			continue
+		}
+		for i := 0; i < len(value.assoc); i += 2 {
+			if i > 0 {
+				builder.WriteString(op.sep)
+			}
+			builder.WriteString(percentEncode(value.assoc[i], op.allowReserved))
+			if op.named && value.assoc[i+1] == "" {
+				builder.WriteString(op.ifEmpty)
+				continue
+			}
+			builder.WriteByte('=')
+			builder.WriteString(percentEncode(value.assoc[i+1], op.allowReserved))
+		}
+	}
+	return nil
+}
+
+// templateVars collects the defined template variables from the fields of the struct v.
+func (c *Converter) templateVars(v interface{}) (map[string]templateValue, error) {
+	vars := make(map[string]templateValue)
+	if v == nil {
+		return vars, nil
+	}
+	vf := reflect.ValueOf(v)
+	if vf.Kind() == reflect.Ptr {
+		if vf.IsNil() {
+			return vars, nil
+		}
+		vf = vf.Elem()
+	}
+	if vf.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+	p, err := c.plan(vf.Type())
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range p.fields {
+		sv, ok := fieldValue(vf, f.index, false)
+		if !ok || f.opts.Contains("remain") || f.until != "" {
+			continue
+		}
+		if f.opts.Contains("omitempty") && isEmptyValue(sv) {
+			continue
+		}
+		value, ok, err := c.templateValue(sv)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.path, err)
+		}
+		if ok {
+			vars[f.name] = value
+		}
+	}
+	return vars, nil
+}
+
+// templateValue converts sv to a template value.
+// The second return value is false if the value is undefined.
+func (c *Converter) templateValue(sv reflect.Value) (templateValue, bool, error) {
+	if sv.Kind() == reflect.Struct && sv.Type().Implements(optionalType) {
+		value, present, null := sv.Interface().(optional).optionalGet()
+		if !present || null {
+			return templateValue{}, false, nil
+		}
+		sv = value
+	}
+	for sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface {
+		if sv.IsNil() {
+			return templateValue{}, false, nil
+		}
+		if sv.Type().Implements(encoderType) {
+			break
+		}
+		sv = sv.Elem()
+	}
+	if s, ok := c.formatValue(sv); ok {
+		return templateValue{kind: templateString, str: s}, true, nil
+	}
+
+	switch {
+	case sv.Type().Implements(encoderType):
+		encoded, err := sv.Interface().(Encoder).Encode()
+		if err != nil {
+			return templateValue{}, false, err
+		}
+		return templateValue{kind: templateList, list: encoded}, len(encoded) > 0, nil
+	case sv.Kind() == reflect.Slice || sv.Kind() == reflect.Array:
+		list := make([]string, sv.Len())
+		for i := range list {
+			s, ok := c.formatValue(sv.Index(i))
+			if !ok {
+				return templateValue{}, false, fmt.Errorf("nested list values are not supported")
+			}
+			list[i] = s
+		}
+		return templateValue{kind: templateList, list: list}, len(list) > 0, nil
+	case sv.Kind() == reflect.Struct || sv.Kind() == reflect.Map:
+		pairs, err := c.objectPairs(sv)
+		if err != nil {
+			return templateValue{}, false, err
+		}
+		return templateValue{kind: templateAssoc, assoc: pairs}, len(pairs) > 0, nil
+	default:
+		return templateValue{}, false, nil
+	}
+}
+
+// Expand expands the URI Template with the fields of the struct v.
+// The fields must have a tag with the key "url".
+func Expand(template string, v interface{}) (string, error) {
+	converter := NewConverter(NewTag())
+	return converter.Expand(template, v)
+}
@@ -0,0 +1,217 @@
+package querystring
+
+import "testing"
+
+type templateKeys struct {
+	Semi  string
+	Dot   string
+	Comma string
+}
+
+// templateTestVars holds the variables of section 3.2 of RFC 6570.
+// Untagged fields are named by the default snake case conversion.
+type templateTestVars struct {
+	Count     []string
+	Dom       []string
+	Dub       string
+	Hello     string
+	Half      string
+	Var       string
+	Who       string
+	Base      string
+	Path      string
+	List      []string
+	Keys      templateKeys
+	V         string
+	X         string
+	Y         string
+	Empty     string
+	EmptyKeys map[string]string
+	Undef     *string
+}
+
+func TestExpand(t *testing.T) {
+	vars := templateTestVars{
+		Count: []string{"one", "two", "three"},
+		Dom:   []string{"example", "com"},
+		Dub:   "me/too",
+		Hello: "Hello World!",
+		Half:  "50%",
+		Var:   "value",
+		Who:   "fred",
+		Base:  "http://example.com/home/",
+		Path:  "/foo/bar",
+		List:  []string{"red", "green", "blue"},
+		Keys:  templateKeys{Semi: ";", Dot: ".", Comma: ","},
+		V:     "6",
+		X:     "1024",
+		Y:     "768",
+	}
+	tests := []struct {
+		template string
+		expected string
+	}{
+		// 3.2.1 Variable Expansion
+		{"{count}", "one,two,three"},
+		{"{count*}", "one,two,three"},
+		{"{/count}", "/one,two,three"},
+		{"{/count*}", "/one/two/three"},
+		{"{;count}", ";count=one,two,three"},
+		{"{;count*}", ";count=one;count=two;count=three"},
+		{"{?count}", "?count=one,two,three"},
+		{"{?count*}", "?count=one&count=two&count=three"},
+		{"{&count*}", "&count=one&count=two&count=three"},
+		// 3.2.2 Simple String Expansion
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+		{"{half}", "50%25"},
+		{"O{empty}X", "OX"},
+		{"O{undef}X", "OX"},
+		{"{x,y}", "1024,768"},
+		{"{x,hello,y}", "1024,Hello%20World%21,768"},
+		{"?{x,empty}", "?1024,"},
+		{"?{x,undef}", "?1024"},
+		{"?{undef,y}", "?768"},
+		{"{var:3}", "val"},
+		{"{var:30}", "value"},
+		{"{list}", "red,green,blue"},
+		{"{list*}", "red,green,blue"},
+		{"{keys}", "semi,%3B,dot,.,comma,%2C"},
+		{"{keys*}", "semi=%3B,dot=.,comma=%2C"},
+		// 3.2.3 Reserved Expansion
+		{"{+var}", "value"},
+		{"{+hello}", "Hello%20World!"},
+		{"{+half}", "50%25"},
+		{"{base}index", "http%3A%2F%2Fexample.com%2Fhome%2Findex"},
+		{"{+base}index", "http://example.com/home/index"},
+		{"O{+empty}X", "OX"},
+		{"O{+undef}X", "OX"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"here?ref={+path}", "here?ref=/foo/bar"},
+		{"up{+path}{var}/here", "up/foo/barvalue/here"},
+		{"{+x,hello,y}", "1024,Hello%20World!,768"},
+		{"{+path,x}/here", "/foo/bar,1024/here"},
+		{"{+path:6}/here", "/foo/b/here"},
+		{"{+list}", "red,green,blue"},
+		{"{+list*}", "red,green,blue"},
+		{"{+keys}", "semi,;,dot,.,comma,,"},
+		{"{+keys*}", "semi=;,dot=.,comma=,"},
+		// 3.2.4 Fragment Expansion
+		{"{#var}", "#value"},
+		{"{#hello}", "#Hello%20World!"},
+		{"{#half}", "#50%25"},
+		{"foo{#empty}", "foo#"},
+		{"foo{#undef}", "foo"},
+		{"{#x,hello,y}", "#1024,Hello%20World!,768"},
+		{"{#path,x}/here", "#/foo/bar,1024/here"},
+		{"{#path:6}/here", "#/foo/b/here"},
+		{"{#list}", "#red,green,blue"},
+		{"{#list*}", "#red,green,blue"},
+		{"{#keys}", "#semi,;,dot,.,comma,,"},
+		{"{#keys*}", "#semi=;,dot=.,comma=,"},
+		// 3.2.5 Label Expansion with Dot-Prefix
+		{"{.who}", ".fred"},
+		{"{.who,who}", ".fred.fred"},
+		{"{.half,who}", ".50%25.fred"},
+		{"www{.dom*}", "www.example.com"},
+		{"X{.var}", "X.value"},
+		{"X{.empty}", "X."},
+		{"X{.undef}", "X"},
+		{"X{.var:3}", "X.val"},
+		{"X{.list}", "X.red,green,blue"},
+		{"X{.list*}", "X.red.green.blue"},
+		{"X{.keys}", "X.semi,%3B,dot,.,comma,%2C"},
+		{"X{.keys*}", "X.semi=%3B.dot=..comma=%2C"},
+		{"X{.empty_keys}", "X"},
+		{"X{.empty_keys*}", "X"},
+		// 3.2.6 Path Segment Expansion
+		{"{/who}", "/fred"},
+		{"{/who,who}", "/fred/fred"},
+		{"{/half,who}", "/50%25/fred"},
+		{"{/who,dub}", "/fred/me%2Ftoo"},
+		{"{/var}", "/value"},
+		{"{/var,empty}", "/value/"},
+		{"{/var,undef}", "/value"},
+		{"{/var,x}/here", "/value/1024/here"},
+		{"{/var:1,var}", "/v/value"},
+		{"{/list}", "/red,green,blue"},
+		{"{/list*}", "/red/green/blue"},
+		{"{/list*,path:4}", "/red/green/blue/%2Ffoo"},
+		{"{/keys}", "/semi,%3B,dot,.,comma,%2C"},
+		{"{/keys*}", "/semi=%3B/dot=./comma=%2C"},
+		// 3.2.7 Path-Style Parameter Expansion
+		{"{;who}", ";who=fred"},
+		{"{;half}", ";half=50%25"},
+		{"{;empty}", ";empty"},
+		{"{;v,empty,who}", ";v=6;empty;who=fred"},
+		{"{;v,bar,who}", ";v=6;who=fred"},
+		{"{;x,y}", ";x=1024;y=768"},
+		{"{;x,y,empty}", ";x=1024;y=768;empty"},
+		{"{;x,y,undef}", ";x=1024;y=768"},
+		{"{;hello:5}", ";hello=Hello"},
+		{"{;list}", ";list=red,green,blue"},
+		{"{;list*}", ";list=red;list=green;list=blue"},
+		{"{;keys}", ";keys=semi,%3B,dot,.,comma,%2C"},
+		{"{;keys*}", ";semi=%3B;dot=.;comma=%2C"},
+		// 3.2.8 Form-Style Query Expansion
+		{"{?who}", "?who=fred"},
+		{"{?half}", "?half=50%25"},
+		{"{?x,y}", "?x=1024&y=768"},
+		{"{?x,y,empty}", "?x=1024&y=768&empty="},
+		{"{?x,y,undef}", "?x=1024&y=768"},
+		{"{?var:3}", "?var=val"},
+		{"{?list}", "?list=red,green,blue"},
+		{"{?list*}", "?list=red&list=green&list=blue"},
+		{"{?keys}", "?keys=semi,%3B,dot,.,comma,%2C"},
+		{"{?keys*}", "?semi=%3B&dot=.&comma=%2C"},
+		// 3.2.9 Form-Style Query Continuation
+		{"{&who}", "&who=fred"},
+		{"{&half}", "&half=50%25"},
+		{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"{&x,y,empty}", "&x=1024&y=768&empty="},
+		{"{&var:3}", "&var=val"},
+		{"{&list}", "&list=red,green,blue"},
+		{"{&list*}", "&list=red&list=green&list=blue"},
+		{"{&keys}", "&keys=semi,%3B,dot,.,comma,%2C"},
+		{"{&keys*}", "&semi=%3B&dot=.&comma=%2C"},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.template, vars)
+		if err != nil {
+			t.Errorf("%s: %v", tt.template, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.template, tt.expected, got)
+		}
+	}
+}
+
+func TestExpandPathAndQuery(t *testing.T) {
+	type Input struct {
+		ID   int    `url:"id"`
+		Type string `url:"type,omitempty"`
+		Sort string `url:"sort"`
+		Page int    `url:"page"`
+	}
+	got, err := Expand("/users/{id}/repos{?type,sort,page}", Input{ID: 42, Sort: "name", Page: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/users/42/repos?sort=name&page=2" {
+		t.Errorf("unexpected expansion %q", got)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	for _, s := range []string{"{var", "var}", "{}", "{=var}", "{var:0}", "{var:abc}", "{va r}"} {
+		if _, err := ParseTemplate(s); err == nil {
+			t.Errorf("%s: expected an error", s)
+		}
+	}
+	if _, err := Expand("{list:3}", struct {
+		List []string `url:"list"`
+	}{List: []string{"a"}}); err == nil {
+		t.Error("expected an error for a prefix on a list")
+	}
+}
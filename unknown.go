@@ -0,0 +1,112 @@
+package querystring
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UnknownKeys is the policy applied by decoding to keys that match no field.
+type UnknownKeys int
+
+const (
+	// IgnoreUnknownKeys ignores unknown keys.
+	IgnoreUnknownKeys UnknownKeys = iota
+	// RejectUnknownKeys fails decoding with an *UnknownKeysError.
+	RejectUnknownKeys
+	// ReportUnknownKeys passes unknown keys to the handler set with WithUnknownKeysHandler.
+	ReportUnknownKeys
+)
+
+// UnknownKeysError is returned by decoding when values hold keys that match no field.
+type UnknownKeysError struct {
+	// Keys are the unknown keys, sorted.
+	Keys []string
+	// Suggestions maps unknown keys to the closest known key, when one is close enough.
+	Suggestions map[string]string
+}
+
+func (e *UnknownKeysError) Error() string {
+	parts := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		parts[i] = fmt.Sprintf("%q", k)
+		if s, ok := e.Suggestions[k]; ok {
+			parts[i] += fmt.Sprintf(" (did you mean %q?)", s)
+		}
+	}
+	return "unknown parameters " + strings.Join(parts, ", ")
+}
+
+// checkUnknown applies the unknown keys policy to the keys of values not claimed by fields.
+func (c *Converter) checkUnknown(values url.Values, fields []field) error {
+	if c.unknownKeys == IgnoreUnknownKeys {
+		return nil
+	}
+	unclaimed := c.unclaimed(values, fields)
+	if len(unclaimed) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(unclaimed))
+	for k := range unclaimed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if c.unknownKeys == ReportUnknownKeys {
+		if c.unknownReport != nil {
+			c.unknownReport(keys)
+		}
+		return nil
+	}
+	var names []string
+	for _, f := range fields {
+		names = append(names, c.knownKeys(f.name, f.typ, make(map[reflect.Type]bool))...)
+	}
+	err := &UnknownKeysError{Keys: keys, Suggestions: make(map[string]string)}
+	for _, k := range keys {
+		if s, ok := suggest(k, names); ok {
+			err.Suggestions[k] = s
+		}
+	}
+	return err
+}
+
+// suggest returns the name closest to key by edit distance, if the distance
+// is small compared to the length of key.
+func suggest(key string, names []string) (string, bool) {
+	best, bestDistance := "", -1
+	for _, name := range names {
+		d := editDistance(key, name)
+		if bestDistance < 0 || d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	if bestDistance < 0 || bestDistance > 2 || bestDistance*3 > len(key) {
+		return "", false
+	}
+	return best, true
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,139 @@
+package querystring
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+type unknownInput struct {
+	Limit  int    `url:"limit"`
+	Offset int    `url:"offset"`
+	Sort   string `url:"sort"`
+}
+
+func TestUnknownKeysError(t *testing.T) {
+	con := NewConverter(NewTag(), WithUnknownKeys(RejectUnknownKeys))
+	values := url.Values{"limt": {"10"}, "offset": {"5"}, "x": {"1"}}
+	var in unknownInput
+	err := con.Decode(values, &in)
+	var unknown *UnknownKeysError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected an UnknownKeysError, got %v", err)
+	}
+	if !reflect.DeepEqual(unknown.Keys, []string{"limt", "x"}) {
+		t.Errorf("unexpected keys %v", unknown.Keys)
+	}
+	if !reflect.DeepEqual(unknown.Suggestions, map[string]string{"limt": "limit"}) {
+		t.Errorf("unexpected suggestions %v", unknown.Suggestions)
+	}
+	if err.Error() != `unknown parameters "limt" (did you mean "limit"?), "x"` {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	if err := con.Decode(url.Values{"limit": {"10"}}, &in); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestUnknownKeysReport(t *testing.T) {
+	var reported []string
+	con := NewConverter(NewTag(), WithUnknownKeysHandler(func(keys []string) {
+		reported = keys
+	}))
+	var in unknownInput
+	if err := con.Decode(url.Values{"sort": {"name"}, "debug": {"1"}}, &in); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reported, []string{"debug"}) {
+		t.Errorf("unexpected reported keys %v", reported)
+	}
+	if in.Sort != "name" {
+		t.Errorf("expected sort to be decoded, got %q", in.Sort)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"limit", "limit", 0},
+		{"limt", "limit", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if d := editDistance(tt.a, tt.b); d != tt.distance {
+			t.Errorf("editDistance(%q, %q) = %d, expected %d", tt.a, tt.b, d, tt.distance)
+		}
+	}
+}
+
+func TestUnknownNestedKeys(t *testing.T) {
+	type Filter struct {
+		Status string `url:"status"`
+		Owner  string `url:"owner"`
+	}
+	type Input struct {
+		Filter Filter            `url:"filter"`
+		Labels map[string]string `url:"labels"`
+		Tags   []string          `url:"tags"`
+	}
+	c := NewConverter(NewTag(), WithStyle(StylePHP), WithUnknownKeys(RejectUnknownKeys))
+
+	valid := url.Values{"filter[status]": {"open"}, "labels[team]": {"core"}, "tags[0]": {"a"}, "tags[1]": {"b"}}
+	var out Input
+	if err := c.Decode(valid, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Filter.Status != "open" || out.Labels["team"] != "core" || len(out.Tags) != 2 {
+		t.Errorf("unexpected decoded value %+v", out)
+	}
+
+	invalid := url.Values{"filter[stauts]": {"x"}, "labels[a][b]": {"y"}, "tags[x]": {"z"}}
+	err := c.Decode(invalid, &Input{})
+	var unknownErr *UnknownKeysError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected an unknown keys error, got %v", err)
+	}
+	if !reflect.DeepEqual(unknownErr.Keys, []string{"filter[stauts]", "labels[a][b]", "tags[x]"}) {
+		t.Errorf("unexpected unknown keys %v", unknownErr.Keys)
+	}
+	if unknownErr.Suggestions["filter[stauts]"] != "filter[status]" {
+		t.Errorf("expected a suggestion for filter[stauts], got %v", unknownErr.Suggestions)
+	}
+}
+
+func TestUnknownExplodedObjectKeys(t *testing.T) {
+	type Color struct {
+		R int `url:"R"`
+		G int `url:"G"`
+		B int `url:"B,omitempty"`
+	}
+	type Input struct {
+		Color Color  `url:"color,style=form,explode=true"`
+		Page  int    `url:"page"`
+		Shade *Color `url:"shade,style=deepObject"`
+	}
+	c := NewConverter(NewTag(), WithUnknownKeys(RejectUnknownKeys))
+	values, err := c.Values(Input{Color: Color{R: 1, G: 2}, Page: 3, Shade: &Color{R: 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("R") != "1" || values.Get("G") != "2" {
+		t.Fatalf("expected the properties as top-level keys, got %v", values)
+	}
+	if err := c.Decode(values, &Input{}); err != nil {
+		t.Errorf("expected the encoded values to be accepted, got %v", err)
+	}
+
+	values.Set("X", "9")
+	err = c.Decode(values, &Input{})
+	var unknownErr *UnknownKeysError
+	if !errors.As(err, &unknownErr) || !reflect.DeepEqual(unknownErr.Keys, []string{"X"}) {
+		t.Errorf("expected X to be unknown, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package querystring
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseVersion parses a version such as "v2" or "v2.1" into its numbers.
+// The leading "v" is optional.
+func parseVersion(s string) ([]int, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	if trimmed == "" {
+		return nil, fmt.Errorf("invalid version %q", s)
+	}
+	parts := strings.Split(trimmed, ".")
+	version := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q", s)
+		}
+		version[i] = n
+	}
+	return version, nil
+}
+
+// compareVersions returns -1, 0 or 1 if a is lower than, equal to or higher than b.
+// Missing numbers are zero, so "v2" equals "v2.0".
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// checkVersion applies the target version of the Converter to a field with
+// the tag options since=<version> and until=<version>.
+// It reports whether the field exists in the target version, that is it was
+// introduced in or before it, and returns the until version if the field is
+// deprecated in the target version.
+// Without a target version every field exists and none is deprecated.
+func (c *Converter) checkVersion(opts TagOptions) (bool, string, error) {
+	if c.version == "" {
+		return true, "", nil
+	}
+	target, err := parseVersion(c.version)
+	if err != nil {
+		return false, "", err
+	}
+	if since, ok := opts.Value("since"); ok {
+		v, err := parseVersion(since)
+		if err != nil {
+			return false, "", err
+		}
+		if compareVersions(target, v) < 0 {
+			return false, "", nil
+		}
+	}
+	if until, ok := opts.Value("until"); ok {
+		v, err := parseVersion(until)
+		if err != nil {
+			return false, "", err
+		}
+		if compareVersions(target, v) >= 0 {
+			return true, until, nil
+		}
+	}
+	return true, "", nil
+}
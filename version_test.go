@@ -0,0 +1,93 @@
+package querystring
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect int
+	}{
+		{"v2", "v2", 0},
+		{"v2", "v2.0", 0},
+		{"v2", "v10", -1},
+		{"v2.1", "v2", 1},
+		{"3", "v2", 1},
+	}
+	for _, test := range tests {
+		a, err := parseVersion(test.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := parseVersion(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := compareVersions(a, b); actual != test.expect {
+			t.Errorf("compareVersions(%q, %q) = %d, expected %d", test.a, test.b, actual, test.expect)
+		}
+	}
+	if _, err := parseVersion("vnext"); err == nil {
+		t.Error("expected an invalid version error")
+	}
+}
+
+func TestVersion(t *testing.T) {
+	type Input struct {
+		Query  string `url:"q"`
+		Cursor string `url:"cursor,since=v2"`
+		Offset int    `url:"offset,until=v3"`
+	}
+	in := Input{Query: "go", Cursor: "abc", Offset: 20}
+
+	tests := []struct {
+		version string
+		expect  url.Values
+	}{
+		{"", url.Values{"q": {"go"}, "cursor": {"abc"}, "offset": {"20"}}},
+		{"v1", url.Values{"q": {"go"}, "offset": {"20"}}},
+		{"v2", url.Values{"q": {"go"}, "cursor": {"abc"}, "offset": {"20"}}},
+		{"v3", url.Values{"q": {"go"}, "cursor": {"abc"}}},
+	}
+	for _, test := range tests {
+		values, err := NewConverter(NewTag(), WithVersion(test.version)).Values(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(values, test.expect) {
+			t.Errorf("version %q: expected %v, got %v", test.version, test.expect, values)
+		}
+	}
+
+	query := url.Values{"q": {"go"}, "cursor": {"abc"}, "offset": {"20"}}
+	var warnings []string
+	handler := func(key, until string) {
+		warnings = append(warnings, key+" "+until)
+	}
+	var out Input
+	c := NewConverter(NewTag(), WithVersion("v4"), WithDeprecationHandler(handler))
+	if err := c.Decode(query, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("expected %+v, got %+v", in, out)
+	}
+	if !reflect.DeepEqual(warnings, []string{"offset v3"}) {
+		t.Errorf("unexpected deprecation warnings %v", warnings)
+	}
+
+	out = Input{}
+	if err := NewConverter(NewTag(), WithVersion("v1")).Decode(query, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Cursor != "" {
+		t.Errorf("decoded a parameter introduced after the target version: %+v", out)
+	}
+
+	if _, err := NewConverter(NewTag(), WithVersion("latest")).Values(in); err == nil {
+		t.Error("expected an invalid version error")
+	}
+}